@@ -0,0 +1,208 @@
+package structmorph
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// fromDirective annotates the destination struct with the source struct and the generation flags,
+// e.g. `//structmorph:from domain.Person --flatten`.
+const fromDirective = "//structmorph:from"
+
+// annotationExcludedFlags are set by the annotation itself or by the run that discovers the annotations.
+var annotationExcludedFlags = map[string]bool{"src": true, "dst": true, "root": true}
+
+// RenderAnnotated finds the destination structs annotated with the structmorph:from directive and the mapping specs
+// in the packages under the root and renders the converters of all the pairs. The packages are loaded once and shared
+// between all the pairs. The options are applied before the annotated ones.
+func RenderAnnotated(root string, opts ...GenerationConfigOption) ([]GeneratedFile, error) {
+	parser := &Parser{ProjectRoot: root}
+	pairs, err := parser.FindAnnotated()
+	if err != nil {
+		return nil, err
+	}
+	specs, err := parser.FindSpecs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		pairs = append(pairs, recordedPair{
+			args: []string{"--dst=" + spec.Dst.Package + "." + spec.Dst.Name, "--src=" + spec.Src.Package + "." + spec.Src.Name},
+			src:  spec.Src,
+			dst:  spec.Dst,
+		})
+	}
+
+	files := make([]GeneratedFile, 0, len(pairs))
+	generatedBy := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		file, err := rerender(parser, pair, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error generating %s: %w", pair.dst.qualifiedName(), err)
+		}
+		err = checkOutputPath(generatedBy, file, pair.dst)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// checkOutputPath records the destination struct the file is generated for and returns an error
+// if another struct is already generated into the same file.
+func checkOutputPath(generatedBy map[string]string, file GeneratedFile, dst StructName) error {
+	if other, ok := generatedBy[file.Path]; ok {
+		return fmt.Errorf("structs %s and %s are generated into the same file: %s", other, dst.qualifiedName(), file.Path)
+	}
+	generatedBy[file.Path] = dst.qualifiedName()
+	return nil
+}
+
+// GenerateAnnotated renders the converters of all the annotated structs and the mapping specs under the root
+// and writes them.
+func GenerateAnnotated(root string, opts ...GenerationConfigOption) error {
+	cfg := newGenerationConfig(opts...)
+	files, err := RenderAnnotated(root, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = writeGenerated(file, cfg)
+		if err != nil {
+			return err
+		}
+	}
+	return updateGroups(pathsOf(files), cfg)
+}
+
+// FindAnnotated returns the struct pairs of the structs annotated with the structmorph:from directive
+// in the loaded packages, sorted by the destination struct.
+func (p *Parser) FindAnnotated() ([]recordedPair, error) {
+	pkgs, err := p.loadPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []recordedPair
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil && len(genDecl.Specs) == 1 {
+						doc = genDecl.Doc
+					}
+					line, ok := annotationOf(doc)
+					if !ok {
+						continue
+					}
+					if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+						return nil, fmt.Errorf("%s: %s annotates non-struct type %s", pkg.Fset.Position(typeSpec.Pos()), fromDirective, typeSpec.Name.Name)
+					}
+
+					pair, err := parseAnnotation(pkg, file, typeSpec.Name.Name, line)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", pkg.Fset.Position(typeSpec.Pos()), err)
+					}
+					slog.Info("Annotated struct found", "struct", pair.dst.qualifiedName(), "src", pair.src.qualifiedName())
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].dst.qualifiedName() < pairs[j].dst.qualifiedName()
+	})
+	return pairs, nil
+}
+
+// annotationOf returns the arguments of the structmorph:from directive in the doc comment.
+func annotationOf(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		rest, ok := strings.CutPrefix(comment.Text, fromDirective)
+		if ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
+
+// parseAnnotation parses the source struct and the flags of the annotation of the destination struct.
+// The source struct without the package is looked up in the package of the destination struct, the source struct
+// with the package is looked up in the package imported by the file under that name, and the source struct with
+// the import path in the package of that path. Any other package is an error, since the package name alone
+// may be shared by several loaded packages.
+func parseAnnotation(pkg *packages.Package, file *ast.File, dstName, line string) (recordedPair, error) {
+	args, err := splitArgs(line)
+	if err != nil {
+		return recordedPair{}, err
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return recordedPair{}, fmt.Errorf("%s requires the source struct, e.g. %s domain.Person", fromDirective, fromDirective)
+	}
+
+	src := args[0]
+	if !strings.Contains(src, ".") {
+		src = pkg.Types.Name() + "." + src
+	}
+
+	pair := recordedPair{dir: filepath.Dir(pkg.Fset.Position(file.Pos()).Filename)}
+	flags := flag.NewFlagSet(fromDirective, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	pair.flags.Register(flags)
+	err = flags.Parse(args[1:])
+	if err != nil {
+		return recordedPair{}, err
+	}
+	if flags.NArg() > 0 {
+		return recordedPair{}, fmt.Errorf("unexpected arguments in %s: %v", fromDirective, flags.Args())
+	}
+	for name := range annotationExcludedFlags {
+		if flags.Lookup(name).Value.String() != "" {
+			return recordedPair{}, fmt.Errorf("flag --%s is not allowed in %s", name, fromDirective)
+		}
+	}
+
+	pair.args = append([]string{"--dst=" + pkg.Types.Name() + "." + dstName, "--src=" + src}, FlagArgs(flags, annotationExcludedFlags)...)
+	sort.Strings(pair.args)
+
+	pair.dst = StructName{Package: pkg.Types.Name(), Name: dstName, Path: pkg.Types.Path()}
+	pair.src, err = ParseStructName(src)
+	if err != nil {
+		return recordedPair{}, err
+	}
+	if pair.src.Path == "" {
+		pair.src.Path = importPathOf(pkg, file.Imports, pair.src.Package)
+	}
+	if pair.src.Path == "" && pair.src.Package == pkg.Types.Name() {
+		pair.src.Path = pkg.Types.Path()
+	}
+	if pair.src.Path == "" {
+		return recordedPair{}, fmt.Errorf("package %s of the source struct is not imported by the file, import it or use the import path, e.g. %s example.com/module/%s.%s",
+			pair.src.Package, fromDirective, pair.src.Package, pair.src.Name)
+	}
+	return pair, nil
+}
@@ -0,0 +1,76 @@
+package structmorph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAnnotated(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "source in another package",
+			dto:  "package dto\n\nimport _ \"example.com/module/domain\"\n\n//structmorph:from domain.User --flatten\ntype UserDTO struct{}\n",
+			want: []string{"example.com/module/domain.User", "example.com/module/dto.UserDTO", "--dst=dto.UserDTO --flatten --src=domain.User"},
+		},
+		{
+			name: "source in package imported under another name",
+			dto:  "package dto\n\nimport model \"example.com/module/domain\"\n\nvar _ model.User\n\n//structmorph:from model.User\ntype UserDTO struct{}\n",
+			want: []string{"example.com/module/domain.User", "example.com/module/dto.UserDTO", "--dst=dto.UserDTO --src=model.User"},
+		},
+		{
+			name: "source by import path",
+			dto:  "package dto\n\n//structmorph:from example.com/module/domain.User\ntype UserDTO struct{}\n",
+			want: []string{"example.com/module/domain.User", "example.com/module/dto.UserDTO", "--dst=dto.UserDTO --src=example.com/module/domain.User"},
+		},
+		{
+			name:    "source in package not imported by the file",
+			dto:     "package dto\n\n//structmorph:from domain.User\ntype UserDTO struct{}\n",
+			wantErr: "package domain of the source struct is not imported by the file",
+		},
+		{
+			name: "source in the same package",
+			dto:  "package dto\n\ntype User struct{}\n\n// UserDTO is the user.\n//\n//structmorph:from User\ntype UserDTO struct{}\n",
+			want: []string{"example.com/module/dto.User", "example.com/module/dto.UserDTO", "--dst=dto.UserDTO --src=dto.User"},
+		},
+		{
+			name:    "no source struct",
+			dto:     "package dto\n\n//structmorph:from --guard\ntype UserDTO struct{}\n",
+			wantErr: "requires the source struct",
+		},
+		{
+			name:    "destination struct is set",
+			dto:     "package dto\n\n//structmorph:from domain.User --dst=dto.Other\ntype UserDTO struct{}\n",
+			wantErr: "flag --dst is not allowed",
+		},
+		{
+			name:    "not a struct",
+			dto:     "package dto\n\n//structmorph:from domain.User\ntype UserDTO []string\n",
+			wantErr: "annotates non-struct type UserDTO",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeModule(t, map[string]string{
+				"domain/user.go": "package domain\n\ntype User struct{}\n",
+				"dto/dto.go":     tt.dto,
+			})
+
+			pairs, err := (&Parser{ProjectRoot: dir}).FindAnnotated()
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, pairs, 1)
+			assert.Equal(t, tt.want, []string{pairs[0].src.qualifiedName(), pairs[0].dst.qualifiedName(), FormatCommand(pairs[0].args)[len("structmorph "):]})
+		})
+	}
+}
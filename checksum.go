@@ -0,0 +1,173 @@
+package structmorph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// commandDirective records the command line the file was generated with.
+	commandDirective = "//structmorph:command "
+	// checksumDirective records the checksum of the file content without the checksum line itself.
+	checksumDirective = "//structmorph:checksum "
+)
+
+// FormatCommand returns the command line recorded in the header of the generated file.
+func FormatCommand(args []string) string {
+	return formatCommand("structmorph", args)
+}
+
+func formatCommand(program string, args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, program)
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t\"'`\\") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ParseCommand splits the command line recorded in the header of the generated file back into the arguments,
+// the leading program name is dropped.
+func ParseCommand(line string) ([]string, error) {
+	args, err := splitArgs(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "structmorph" {
+		return nil, fmt.Errorf("invalid command: %s", line)
+	}
+	return args[1:], nil
+}
+
+// splitArgs splits the line into the arguments separated by spaces, the arguments may be quoted as Go strings.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for rest := strings.TrimSpace(line); rest != ""; rest = strings.TrimLeft(rest, " \t") {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument: %s", rest)
+			}
+			arg, _ := strconv.Unquote(quoted)
+			args = append(args, arg)
+			rest = rest[len(quoted):]
+			continue
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		args = append(args, rest[:end])
+		rest = rest[end:]
+	}
+	return args, nil
+}
+
+// recordedCommand returns the command line recorded in the header of the generated file.
+func recordedCommand(content []byte) (string, bool) {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, commandDirective) {
+			return strings.TrimPrefix(line, commandDirective), true
+		}
+		if strings.HasPrefix(line, "package ") {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// AddChecksum inserts the checksum line after the command line, or the group directive of the group file,
+// of the generated file.
+func AddChecksum(content []byte) []byte {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	for i, line := range lines {
+		if bytes.HasPrefix(line, []byte(commandDirective)) || bytes.HasPrefix(line, []byte(groupDirective)) {
+			checksum := []byte(checksumDirective + checksumOf(content) + "\n")
+			result := make([]byte, 0, len(content)+len(checksum))
+			result = append(result, bytes.Join(lines[:i+1], nil)...)
+			result = append(result, checksum...)
+			return append(result, bytes.Join(lines[i+1:], nil)...)
+		}
+	}
+	return content
+}
+
+// VerifyChecksum reports whether the generated file is unchanged since it was generated.
+// The files without the checksum, e.g. generated by an older version, are considered unchanged.
+func VerifyChecksum(content []byte) bool {
+	var recorded string
+	var rest [][]byte
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if recorded == "" && bytes.HasPrefix(line, []byte(checksumDirective)) {
+			recorded = strings.TrimSpace(strings.TrimPrefix(string(line), checksumDirective))
+			continue
+		}
+		rest = append(rest, line)
+	}
+	if recorded == "" {
+		return true
+	}
+	return recorded == checksumOf(bytes.Join(rest, nil))
+}
+
+// Verify walks the directory and returns the generated files that were modified by hand, sorted by path.
+func Verify(root string) ([]string, error) {
+	var tampered []string
+	err := walkGeneratedFiles(root, func(path string, content []byte) error {
+		if !VerifyChecksum(content) {
+			tampered = append(tampered, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(tampered)
+	return tampered, nil
+}
+
+// walkGeneratedFiles calls the function for every Go file under the root generated by structmorph,
+// the hidden directories, vendor and testdata are skipped.
+func walkGeneratedFiles(root string, fn func(path string, content []byte) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		if !IsGenerated(content) {
+			return nil
+		}
+		return fn(path, content)
+	})
+}
+
+func checksumOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package structmorph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const generatedContent = GeneratedHeader + `
+//structmorph:command structmorph --dst=main.PersonDTO --src=main.Person
+
+package main
+
+func ConvertToPersonDTO(src Person) PersonDTO {
+	return PersonDTO{}
+}
+`
+
+func TestChecksum(t *testing.T) {
+	content := AddChecksum([]byte(generatedContent))
+
+	assert.Contains(t, string(content), "\n"+checksumDirective+"sha256:")
+	assert.True(t, VerifyChecksum(content))
+	assert.True(t, VerifyChecksum([]byte(generatedContent)), "file without checksum is considered unchanged")
+
+	tampered := []byte(string(content) + "\nfunc HandWritten() {}\n")
+	assert.False(t, VerifyChecksum(tampered))
+}
+
+func TestParseCommand(t *testing.T) {
+	args := []string{"--dst=main.PersonDTO", "--guard", "--trim-prefix=Dto Old", `--match=tag:"json"`, "--src=main.Person"}
+
+	parsed, err := ParseCommand(FormatCommand(args))
+	require.NoError(t, err)
+	assert.Equal(t, args, parsed)
+
+	line, ok := recordedCommand([]byte(generatedContent))
+	require.True(t, ok)
+	parsed, err = ParseCommand(line)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--dst=main.PersonDTO", "--src=main.Person"}, parsed)
+
+	_, err = ParseCommand(`gofmt -w .`)
+	assert.Error(t, err)
+	_, err = ParseCommand(`structmorph "--src=unterminated`)
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	content := AddChecksum([]byte(generatedContent))
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("morph_person.go", string(content))
+	write("sub/morph_person.go", string(content)+"// hand edit\n")
+	write("person.go", "package main\n")
+	write(".hidden/morph_person.go", string(content)+"// hand edit\n")
+
+	tampered, err := Verify(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "sub", "morph_person.go")}, tampered)
+	assert.NoError(t, checkOverwrite(filepath.Join(dir, "morph_person.go")))
+	assert.ErrorContains(t, checkOverwrite(filepath.Join(dir, "sub", "morph_person.go")), "modified by hand")
+}
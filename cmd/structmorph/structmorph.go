@@ -2,37 +2,237 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"strings"
 	"structmorph"
 )
 
 var (
-	from = flag.String("src", "", "Source struct name")
-	to   = flag.String("dst", "", "Destination struct name")
-	root = flag.String("root", "", "Root directory")
+	genFlags structmorph.GenerationFlags
+	check    = flag.Bool("check", false, "Compare the generated code with the file on disk without writing it, exit with 1 if they differ")
+	force    = flag.Bool("force", false, "Overwrite the destination file even if it was not generated by structmorph")
+	stdout   = flag.Bool("stdout", false, "Print the generated code to stdout instead of writing the file")
+	dryRun   = flag.Bool("dry-run", false, "Alias for --stdout")
 )
 
+func init() {
+	genFlags.Register(flag.CommandLine)
+}
+
+// modeFlags do not affect the generated code, so they are not recorded in its header.
+var modeFlags = map[string]bool{"check": true, "force": true, "stdout": true, "dry-run": true}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		runVerify(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "prune" {
+		runPrune(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "regen" {
+		runRegen(os.Args[2:])
+		return
+	}
+
 	parseArgs()
-	slog.Info("Parsed arguments", "src", from, "dst", to)
+	if flag.NArg() > 0 {
+		runAnnotated(dirOfPattern(flag.CommandLine))
+		return
+	}
+	slog.Info("Parsed arguments", "src", genFlags.Src, "dst", genFlags.Dst)
+
+	opts, err := genFlags.Options()
+	if err != nil {
+		log.Fatalf("Error parsing arguments: %v", err)
+	}
+	opts = append(opts, structmorph.WithCommand(recordedArgs()...))
+	if *force {
+		opts = append(opts, structmorph.WithForce())
+	}
+
+	if *check {
+		diff, err := structmorph.Check(genFlags.Src, genFlags.Dst, opts...)
+		if err != nil {
+			log.Fatalf("Error checking code: %v", err)
+		}
+		if diff != "" {
+			fmt.Print(diff)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *stdout || *dryRun {
+		file, err := structmorph.Render(genFlags.Src, genFlags.Dst, opts...)
+		if err != nil {
+			log.Fatalf("Error generating code: %v", err)
+		}
+		slog.Info("Generated code is not written", "file", file.Path)
+		os.Stdout.Write(file.Content)
+		return
+	}
+
+	if err := structmorph.Generate(genFlags.Src, genFlags.Dst, opts...); err != nil {
+		log.Fatalf("Error generating code: %v", err)
+	}
+}
+
+// runAnnotated generates the converters of all the structs annotated with the structmorph:from directive
+// and of all the mapping specs in the packages under the directory.
+func runAnnotated(dir string) {
+	// the generation flags are set per struct by the annotations, so that they are recorded in the headers
+	if args := recordedArgs(); len(args) > 0 || *check {
+		log.Fatalf("Error parsing arguments: only --force and --dry-run can be used with package patterns")
+	}
 
 	var opts []structmorph.GenerationConfigOption
-	if *root != "" {
-		opts = append(opts, structmorph.WithProjectRoot(*root))
+	if *force {
+		opts = append(opts, structmorph.WithForce())
+	}
+
+	if *stdout || *dryRun {
+		files, err := structmorph.RenderAnnotated(dir, opts...)
+		if err != nil {
+			log.Fatalf("Error generating code: %v", err)
+		}
+		for _, file := range files {
+			slog.Info("Generated code is not written", "file", file.Path)
+			os.Stdout.Write(file.Content)
+		}
+		return
 	}
 
-	if err := structmorph.Generate(*from, *to, opts...); err != nil {
+	if err := structmorph.GenerateAnnotated(dir, opts...); err != nil {
 		log.Fatalf("Error generating code: %v", err)
 	}
 }
 
+// runVerify lists the generated files modified by hand, it exits with 1 if there are any.
+func runVerify(args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: structmorph verify [dir]")
+	}
+	_ = flags.Parse(args)
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	tampered, err := structmorph.Verify(dir)
+	if err != nil {
+		log.Fatalf("Error verifying generated files: %v", err)
+	}
+	for _, file := range tampered {
+		fmt.Println(file)
+	}
+	if len(tampered) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runRegen regenerates the generated files under the directory from the command lines recorded in their headers.
+func runRegen(args []string) {
+	flags := flag.NewFlagSet("regen", flag.ExitOnError)
+	force := flags.Bool("force", false, "Overwrite the generated files even if they were modified by hand")
+	prune := flags.Bool("prune", false, "Remove the generated files whose struct pair no longer exists")
+	dryRun := flags.Bool("dry-run", false, "With --prune, print the orphaned files without regenerating or removing anything")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: structmorph regen [--force] [--prune [--dry-run]] [dir/...]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	dir := dirOfPattern(flags)
+	if *dryRun {
+		if !*prune {
+			log.Fatalf("Error parsing arguments: --dry-run can only be used with --prune")
+		}
+		printOrphans(dir)
+		return
+	}
+
+	var opts []structmorph.GenerationConfigOption
+	if *force {
+		opts = append(opts, structmorph.WithForce())
+	}
+	if *prune {
+		opts = append(opts, structmorph.WithPruning())
+	}
+	if err := structmorph.Regenerate(dir, opts...); err != nil {
+		log.Fatalf("Error regenerating code: %v", err)
+	}
+}
+
+// runPrune removes the generated files under the directory whose struct pair no longer exists.
+func runPrune(args []string) {
+	flags := flag.NewFlagSet("prune", flag.ExitOnError)
+	force := flags.Bool("force", false, "Remove the orphaned files even if they were modified by hand")
+	dryRun := flags.Bool("dry-run", false, "Print the orphaned files without removing them")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: structmorph prune [--dry-run] [--force] [dir/...]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	dir := dirOfPattern(flags)
+	if *dryRun {
+		printOrphans(dir)
+		return
+	}
+
+	var opts []structmorph.GenerationConfigOption
+	if *force {
+		opts = append(opts, structmorph.WithForce())
+	}
+	removed, err := structmorph.Prune(dir, opts...)
+	if err != nil {
+		log.Fatalf("Error pruning orphaned files: %v", err)
+	}
+	for _, file := range removed {
+		fmt.Println(file)
+	}
+}
+
+// printOrphans prints the orphaned generated files under the directory without removing them.
+func printOrphans(dir string) {
+	orphans, err := structmorph.Orphans(dir)
+	if err != nil {
+		log.Fatalf("Error finding orphaned files: %v", err)
+	}
+	for _, file := range orphans {
+		fmt.Println(file)
+	}
+}
+
+// dirOfPattern returns the directory of the package pattern passed to the subcommand, e.g. `.` for `./...`.
+func dirOfPattern(flags *flag.FlagSet) string {
+	if flags.NArg() == 0 {
+		return "."
+	}
+	dir := strings.TrimSuffix(strings.TrimSuffix(flags.Arg(0), "..."), "/")
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+// recordedArgs returns the explicitly set flags that affect the generated code, sorted by name.
+func recordedArgs() []string {
+	return structmorph.FlagArgs(flag.CommandLine, modeFlags)
+}
+
 func parseArgs() {
 	flag.Parse()
 
-	if *from == "" || *to == "" {
-		slog.Error("Usage: structmorph --from=domain.Person --to=main.PersonDTO")
+	if flag.NArg() == 0 && (genFlags.Src == "" || genFlags.Dst == "") {
+		slog.Error("Usage: structmorph --from=domain.Person --to=main.PersonDTO or structmorph ./...")
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,231 @@
+package structmorph
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/imports"
+)
+
+// groupDirective marks the converter interface of the struct pair as a member of the group in the doc comment
+// of the interface, and the file that declares the group in the header of the group file.
+const groupDirective = "//structmorph:group "
+
+// Converter is the interface declared for the conversions of the struct pair together with its zero-size
+// implementation, so the services accept the interface and the tests mock it.
+type Converter struct {
+	// Name is the name of the interface, e.g. PersonConverter.
+	Name string
+	// Impl is the name of the zero-size struct implementing the interface, e.g. PersonConverterImpl.
+	Impl string
+	// Group is the name of the interface that embeds the converters of several struct pairs of the package,
+	// it is empty if the converter is not grouped.
+	Group string
+	// ToDTO and ToStruct are the names of the interface methods converting to the destination and source struct
+	// respectively, e.g. ToPersonDTO and ToPerson.
+	ToDTO    string
+	ToStruct string
+}
+
+// WithConverter declares the interface of the conversions and its zero-size implementation, e.g. PersonConverter
+// and PersonConverterImpl, in the generated file.
+func WithConverter() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Converter = true
+	}
+}
+
+// WithConverterGroup declares the converter of the struct pair and adds it to the group interface. The group embeds
+// the converters of all the struct pairs of the destination package generated into it, it is declared in its own
+// generated file, e.g. Mapper and MapperImpl in morph_mapper.go, which is updated whenever the files are written.
+func WithConverterGroup(name string) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Converter = true
+		cfg.ConverterGroup = name
+	}
+}
+
+func createConverter(srcStruct SrcStructType, dstStruct DstStructType, group string) (*Converter, error) {
+	if group != "" && !token.IsIdentifier(group) {
+		return nil, fmt.Errorf("invalid converter group name: %s", group)
+	}
+	name := srcStruct.Name + "Converter"
+	return &Converter{
+		Name:     name,
+		Impl:     name + "Impl",
+		Group:    group,
+		ToDTO:    "To" + dstStruct.Name,
+		ToStruct: "To" + srcStruct.Name,
+	}, nil
+}
+
+// converterGroup is the data of the group file template.
+type converterGroup struct {
+	Header  string
+	Package string
+	Name    string
+	// Converters are the names of the embedded converter interfaces, the implementations have the Impl suffix.
+	Converters []string
+}
+
+var tmplGroup = template.Must(template.New("group").Parse(`{{.Header}}
+
+package {{.Package}}
+
+// {{.Name}} embeds the converters of the struct pairs generated into the group.
+type {{.Name}} interface {
+	{{range .Converters}}{{.}}
+	{{end}}
+}
+
+// {{.Name}}Impl implements {{.Name}} with the zero-size implementations of the converters.
+type {{.Name}}Impl struct {
+	{{range .Converters}}{{.}}Impl
+	{{end}}
+}
+`))
+
+// updateGroups renders the group files in the directories of the written or removed files again from the converters
+// declared in the generated files next to them. The group files without any converter left are removed.
+func updateGroups(paths []string, cfg *GenerationConfig) error {
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		dirs[filepath.Dir(path)] = true
+	}
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+
+	for _, dir := range sorted {
+		err := updateGroupsIn(dir, cfg)
+		if err != nil {
+			return fmt.Errorf("error updating converter groups in %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func updateGroupsIn(dir string, cfg *GenerationConfig) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading directory: %w", err)
+	}
+
+	groupFiles := make(map[string]string)
+	groups := make(map[string]*converterGroup)
+	generated := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		if !IsGenerated(content) {
+			continue
+		}
+		generated[path] = true
+		if name, ok := recordedGroup(content); ok {
+			groupFiles[name] = path
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, content, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("error parsing generated file: %w", err)
+		}
+		for name, converter := range groupedConverters(file) {
+			group, ok := groups[name]
+			if !ok {
+				group = &converterGroup{Header: GeneratedHeader + "\n" + groupDirective + name, Package: file.Name.Name, Name: name}
+				groups[name] = group
+			}
+			group.Converters = append(group.Converters, converter)
+		}
+	}
+
+	for name, group := range groups {
+		path := filepath.Join(dir, groupFileName(name))
+		if generated[path] && groupFiles[name] != path {
+			return fmt.Errorf("group file collides with the generated file: %s", path)
+		}
+		sort.Strings(group.Converters)
+
+		buff := &bytes.Buffer{}
+		err = tmplGroup.Execute(buff, group)
+		if err != nil {
+			return fmt.Errorf("error generating group: %w", err)
+		}
+		content, err := imports.Process(path, buff.Bytes(), nil)
+		if err != nil {
+			return fmt.Errorf("error formatting group: %w", err)
+		}
+		err = writeGenerated(GeneratedFile{Path: path, Content: AddChecksum(content)}, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	var empty []string
+	for name, path := range groupFiles {
+		if groups[name] == nil {
+			empty = append(empty, path)
+		}
+	}
+	sort.Strings(empty)
+	return removeOrphans(empty, cfg)
+}
+
+// groupedConverters returns the converter interfaces of the file marked as the group members by the group names.
+func groupedConverters(file *ast.File) map[string]string {
+	converters := make(map[string]string)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE || gen.Doc == nil || len(gen.Specs) != 1 {
+			continue
+		}
+		for _, comment := range gen.Doc.List {
+			if name, ok := strings.CutPrefix(comment.Text, groupDirective); ok {
+				converters[strings.TrimSpace(name)] = gen.Specs[0].(*ast.TypeSpec).Name.Name
+			}
+		}
+	}
+	return converters
+}
+
+// recordedGroup returns the name of the group declared by the group file.
+func recordedGroup(content []byte) (string, bool) {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, groupDirective) {
+			return strings.TrimSpace(strings.TrimPrefix(line, groupDirective)), true
+		}
+		if strings.HasPrefix(line, "package ") {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func pathsOf(files []GeneratedFile) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func groupFileName(name string) string {
+	return fmt.Sprintf("morph_%s.go", strings.ToLower(name))
+}
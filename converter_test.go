@@ -0,0 +1,64 @@
+package structmorph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMember(t *testing.T, dir, src, group string) string {
+	doc := "// " + src + "Converter converts " + src + ".\n"
+	if group != "" {
+		doc += "//\n" + groupDirective + group + "\n"
+	}
+	path := filepath.Join(dir, "morph_"+strings.ToLower(src)+".go")
+	content := GeneratedHeader + "\n" + commandDirective + "structmorph --src=dto." + src + "\n\npackage dto\n\n" +
+		doc + "type " + src + "Converter interface{}\n\ntype " + src + "ConverterImpl struct{}\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestUpdateGroups(t *testing.T) {
+	dir := t.TempDir()
+	person := writeMember(t, dir, "Person", "Mapper")
+	writeMember(t, dir, "Order", "Mapper")
+	writeMember(t, dir, "Address", "")
+
+	err := updateGroups([]string{person}, DefaultGenerationConfig())
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "morph_mapper.go"))
+	require.NoError(t, err)
+	assert.True(t, VerifyChecksum(content))
+	group, ok := recordedGroup(content)
+	assert.True(t, ok)
+	assert.Equal(t, "Mapper", group)
+	assert.Contains(t, string(content), "type Mapper interface {\n\tOrderConverter\n\tPersonConverter\n}")
+	assert.Contains(t, string(content), "type MapperImpl struct {\n\tOrderConverterImpl\n\tPersonConverterImpl\n}")
+}
+
+func TestUpdateGroups__removed(t *testing.T) {
+	dir := t.TempDir()
+	person := writeMember(t, dir, "Person", "Mapper")
+	require.NoError(t, updateGroups([]string{person}, DefaultGenerationConfig()))
+
+	// the converter is no longer grouped
+	writeMember(t, dir, "Person", "")
+	err := updateGroups([]string{person}, DefaultGenerationConfig())
+
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "morph_mapper.go"))
+}
+
+func TestUpdateGroups__collision(t *testing.T) {
+	dir := t.TempDir()
+	person := writeMember(t, dir, "Person", "Person")
+
+	err := updateGroups([]string{person}, DefaultGenerationConfig())
+
+	assert.ErrorContains(t, err, "group file collides with the generated file")
+}
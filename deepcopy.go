@@ -0,0 +1,280 @@
+package structmorph
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// copyTag is the struct tag overriding the copy mode of the field, either deep or shallow.
+// It is looked up on the destination field first and then on the source field.
+const copyTag = "morphcopy"
+
+// WithDeepCopy copies the slices, maps and pointed-to values of the fields recursively, so the converted struct
+// shares no memory with the original one. Every copied type gets its own helper function in the generated file,
+// the helpers of the recursive types call themselves, so the values are copied to any depth. The fields
+// of the interface, channel and function types and the unexported fields of the structs from other packages
+// are copied shallowly. The copy mode of a field is overridden by the morphcopy:"deep" or morphcopy:"shallow" tag.
+func WithDeepCopy() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.DeepCopy = true
+	}
+}
+
+// CopyFunc is the helper function of the generated file deep-copying the values of the type.
+type CopyFunc struct {
+	Name string
+	// Type is the copied type qualified for the destination package.
+	Type string
+	// Body is the statements of the function copying its parameter v.
+	Body string
+}
+
+// cloner renders the expressions deep-copying the values in the generated code of the destination package
+// together with the helper functions they call.
+type cloner struct {
+	deep    bool
+	dstPath string
+	// prefix starts the names of the helpers, so the helpers of the files of the same package do not collide.
+	prefix string
+	// imports are the import paths of the packages referenced by the helpers.
+	imports map[string]bool
+	// helpers are the names of the helpers by the copied types.
+	helpers typeutil.Map
+	names   map[string]bool
+	funcs   []CopyFunc
+}
+
+func newCloner(prefix, dstPath string, deep bool) *cloner {
+	return &cloner{deep: deep, dstPath: dstPath, prefix: prefix, imports: make(map[string]bool), names: make(map[string]bool)}
+}
+
+// cloneFields deep-copies the values of the fields assigned as they are or through the pointers.
+func (c *cloner) cloneFields(t *TemplateData) error {
+	for i, field := range t.Fields {
+		switch field.Kind {
+		case KindDirect, KindRef, KindDeref, KindGuarded:
+		default:
+			continue
+		}
+		deep, err := c.isDeep(field)
+		if err != nil {
+			return err
+		}
+		if !deep {
+			continue
+		}
+
+		if expr, ok := c.cloneExpr(fieldGoType(field.DstField.Type), field.ValueToDTO()); ok {
+			t.Fields[i].SrcField.OverriddenName = expr
+		}
+		if field.IsOneWay() {
+			continue
+		}
+		if expr, ok := c.cloneExpr(fieldGoType(field.SrcField.Type), field.ValueToStruct()); ok {
+			t.Fields[i].DstField.OverriddenName = expr
+		}
+	}
+	return nil
+}
+
+// isDeep reports whether the field is deep-copied, the copy tag of the field overrides the default mode.
+func (c *cloner) isDeep(field FieldMapping) (bool, error) {
+	for _, tag := range []FieldType{field.DstField.FieldType, field.SrcField.FieldType} {
+		mode, ok := tag.Tag.Lookup(copyTag)
+		if !ok {
+			continue
+		}
+		switch mode {
+		case "deep":
+			return true, nil
+		case "shallow":
+			return false, nil
+		}
+		return false, fmt.Errorf("invalid copy mode, field: %s, tag: %s", tag.Name, mode)
+	}
+	return c.deep, nil
+}
+
+// sortedImports returns the import paths referenced by the rendered expressions.
+func (c *cloner) sortedImports() []string {
+	paths := make([]string, 0, len(c.imports))
+	for path := range c.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// cloneExpr returns the expression deep-copying the value of the expression, it reports false if the type has
+// nothing to copy.
+func (c *cloner) cloneExpr(t types.Type, expr string) (string, bool) {
+	if !c.needsCopy(t) {
+		return expr, false
+	}
+	if slice, ok := t.Underlying().(*types.Slice); ok && !c.needsCopy(slice.Elem()) {
+		if strings.HasPrefix(expr, "*") {
+			expr = "(" + expr + ")"
+		}
+		// the same as slices.Clone, the nil slice stays nil
+		return fmt.Sprintf("append(%s[:0:0], %s...)", expr, expr), true
+	}
+	return fmt.Sprintf("%s(%s)", c.helper(t), expr), true
+}
+
+// needsCopy reports whether the value of the type refers to the memory that is copied. The recursive types refer
+// to themselves through the pointers, slices or maps, so the check always ends.
+func (c *cloner) needsCopy(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return true
+	case *types.Array:
+		return c.needsCopy(u.Elem())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if c.accessible(u.Field(i)) && c.needsCopy(u.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessible reports whether the field can be set in the destination package.
+func (c *cloner) accessible(field *types.Var) bool {
+	return field.Exported() || field.Pkg() != nil && field.Pkg().Path() == c.dstPath
+}
+
+// helper returns the name of the helper copying the type and renders the helper on the first use. The name is
+// registered before the body is rendered, so the helpers of the recursive types call themselves.
+func (c *cloner) helper(t types.Type) string {
+	if name, ok := c.helpers.At(t).(string); ok {
+		return name
+	}
+
+	name := c.prefix + c.typeIdent(t)
+	for i := 2; c.names[name]; i++ {
+		name = fmt.Sprintf("%s%s%d", c.prefix, c.typeIdent(t), i)
+	}
+	c.names[name] = true
+	c.helpers.Set(t, name)
+
+	c.funcs = append(c.funcs, CopyFunc{Name: name, Type: c.typeString(t)})
+	i := len(c.funcs) - 1
+	c.funcs[i].Body = c.helperBody(t, c.funcs[i].Type)
+	return name
+}
+
+// helperBody returns the statements copying the parameter v of the helper.
+func (c *cloner) helperBody(t types.Type, typeName string) string {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		value, _ := c.cloneExpr(u.Elem(), "*v")
+		ref := "&r"
+		if _, ok := t.(*types.Named); ok {
+			ref = fmt.Sprintf("%s(&r)", typeName)
+		}
+		return fmt.Sprintf("if v == nil {\nreturn nil\n}\nr := %s\nreturn %s", value, ref)
+	case *types.Slice:
+		elem, _ := c.cloneExpr(u.Elem(), "e")
+		return fmt.Sprintf("if v == nil {\nreturn nil\n}\nr := make(%s, len(v))\nfor i, e := range v {\nr[i] = %s\n}\nreturn r", typeName, elem)
+	case *types.Map:
+		elem, _ := c.cloneExpr(u.Elem(), "e")
+		return fmt.Sprintf("if v == nil {\nreturn nil\n}\nr := make(%s, len(v))\nfor k, e := range v {\nr[k] = %s\n}\nreturn r", typeName, elem)
+	case *types.Array:
+		elem, _ := c.cloneExpr(u.Elem(), "e")
+		return fmt.Sprintf("for i, e := range v {\nv[i] = %s\n}\nreturn v", elem)
+	case *types.Struct:
+		var sets []string
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !c.accessible(field) {
+				continue
+			}
+			if value, ok := c.cloneExpr(field.Type(), "v."+field.Name()); ok {
+				sets = append(sets, fmt.Sprintf("v.%s = %s\n", field.Name(), value))
+			}
+		}
+		return strings.Join(sets, "") + "return v"
+	}
+	return "return v"
+}
+
+// typeIdent returns the type as a part of the identifier, e.g. PtrAddress for *Address or MapStringSliceInt
+// for map[string][]int.
+func (c *cloner) typeIdent(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Named:
+		name := t.Obj().Name()
+		if pkg := t.Obj().Pkg(); pkg != nil && pkg.Path() != c.dstPath {
+			name = upperFirst(pkg.Name()) + name
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			name += c.typeIdent(t.TypeArgs().At(i))
+		}
+		return name
+	case *types.Basic:
+		return upperFirst(t.Name())
+	case *types.Pointer:
+		return "Ptr" + c.typeIdent(t.Elem())
+	case *types.Slice:
+		return "Slice" + c.typeIdent(t.Elem())
+	case *types.Array:
+		return fmt.Sprintf("Array%d%s", t.Len(), c.typeIdent(t.Elem()))
+	case *types.Map:
+		return "Map" + c.typeIdent(t.Key()) + c.typeIdent(t.Elem())
+	}
+	return "Value"
+}
+
+func upperFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// typeString returns the type qualified for the destination package and records the referenced packages.
+func (c *cloner) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == c.dstPath {
+			return ""
+		}
+		c.imports[pkg.Path()] = true
+		return pkg.Name()
+	})
+}
+
+// mergeImports returns the sorted union of the import paths except the excluded one.
+func mergeImports(imports, more []string, exclude string) []string {
+	seen := map[string]bool{exclude: true}
+	var result []string
+	for _, path := range append(imports[:len(imports):len(imports)], more...) {
+		if !seen[path] {
+			seen[path] = true
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// fieldGoType returns the type-checked type of the field including the pointer, or an invalid type if it is not known.
+func fieldGoType(t FieldTypeType) types.Type {
+	if t.GoType == nil {
+		return types.Typ[types.Invalid]
+	}
+	if t.IsPointer {
+		return types.NewPointer(t.GoType)
+	}
+	return t.GoType
+}
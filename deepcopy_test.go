@@ -0,0 +1,147 @@
+package structmorph
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloner_cloneExpr(t *testing.T) {
+	pkg := types.NewPackage("example.com/module/domain", "domain")
+	address := types.NewNamed(types.NewTypeName(0, pkg, "Address", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "City", types.Typ[types.String], false),
+		types.NewField(0, pkg, "lines", types.NewSlice(types.Typ[types.String]), false),
+	}, nil), nil)
+
+	tests := []struct {
+		name   string
+		goType types.Type
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "basic",
+			goType: types.Typ[types.Int],
+			want:   "src.X",
+		},
+		{
+			name:   "slice",
+			goType: types.NewSlice(types.Typ[types.String]),
+			want:   "append(src.X[:0:0], src.X...)",
+			wantOk: true,
+		},
+		{
+			name:   "pointer",
+			goType: types.NewPointer(types.Typ[types.String]),
+			want:   "personCopyPtrString(src.X)",
+			wantOk: true,
+		},
+		{
+			name:   "struct with unexported field of another package",
+			goType: address,
+			want:   "src.X",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCloner("personCopy", "example.com/module/api", true)
+
+			got, ok := c.cloneExpr(tt.goType, "src.X")
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCloner_helper(t *testing.T) {
+	pkg := types.NewPackage("example.com/module/domain", "domain")
+	node := types.NewNamed(types.NewTypeName(0, pkg, "Node", nil), nil, nil)
+	node.SetUnderlying(types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Next", types.NewPointer(node), false),
+		types.NewField(0, pkg, "Tags", types.NewSlice(types.Typ[types.String]), false),
+	}, nil))
+	c := newCloner("personCopy", "example.com/module/api", true)
+
+	got, ok := c.cloneExpr(types.NewPointer(node), "src.Head")
+	require.True(t, ok)
+	again, _ := c.cloneExpr(types.NewPointer(node), "src.Tail")
+
+	assert.Equal(t, "personCopyPtrDomainNode(src.Head)", got)
+	assert.Equal(t, "personCopyPtrDomainNode(src.Tail)", again, "the type copied twice reuses its helper")
+	assert.Equal(t, []CopyFunc{
+		{
+			Name: "personCopyPtrDomainNode",
+			Type: "*domain.Node",
+			Body: "if v == nil {\nreturn nil\n}\nr := personCopyDomainNode(*v)\nreturn &r",
+		},
+		{
+			Name: "personCopyDomainNode",
+			Type: "domain.Node",
+			Body: "v.Next = personCopyPtrDomainNode(v.Next)\nv.Tags = append(v.Tags[:0:0], v.Tags...)\nreturn v",
+		},
+	}, c.funcs)
+	assert.Equal(t, []string{"example.com/module/domain"}, c.sortedImports())
+}
+
+func TestCloner_isDeep(t *testing.T) {
+	field := func(dstTag, srcTag string) FieldMapping {
+		return FieldMapping{
+			DstField: DstFieldType{FieldType: FieldType{Name: "Tags", Tag: reflect.StructTag(dstTag)}},
+			SrcField: SrcFieldType{FieldType: FieldType{Name: "Tags", Tag: reflect.StructTag(srcTag)}},
+		}
+	}
+	c := newCloner("personCopy", "example.com/module/api", false)
+
+	deep, err := c.isDeep(field("", ""))
+	require.NoError(t, err)
+	assert.False(t, deep)
+
+	deep, err = c.isDeep(field("", `morphcopy:"deep"`))
+	require.NoError(t, err)
+	assert.True(t, deep, "source tag overrides the default")
+
+	deep, err = c.isDeep(field(`morphcopy:"shallow"`, `morphcopy:"deep"`))
+	require.NoError(t, err)
+	assert.False(t, deep, "destination tag takes precedence")
+
+	_, err = c.isDeep(field(`morphcopy:"full"`, ""))
+	assert.ErrorContains(t, err, "invalid copy mode")
+}
+
+func TestCreateTemplateData__deepCopyOfNestedFieldIsEvaluatedOnce(t *testing.T) {
+	pkg := types.NewPackage("example.com/module/domain", "domain")
+	lines := types.NewSlice(types.Typ[types.String])
+	address := types.NewNamed(types.NewTypeName(0, pkg, "Address", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Lines", lines, false),
+	}, nil), nil)
+	contact := types.NewNamed(types.NewTypeName(0, pkg, "Contact", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Address", types.NewPointer(address), false),
+	}, nil), nil)
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "domain", Name: "Contact"},
+		ImportPath: pkg.Path(),
+		GoType:     contact,
+		Fields: map[string]SrcFieldType{
+			"Address": {FieldType: FieldType{Name: "Address", Type: FieldTypeType{Name: "Address", IsPointer: true, GoType: address}}},
+		},
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "domain", Name: "ContactDTO"},
+		ImportPath: pkg.Path(),
+		Fields: []DstFieldType{
+			{FieldType: FieldType{Name: "Lines", Type: FieldTypeType{Name: "[]string", GoType: lines}}, SrcField: "Address.Lines"},
+		},
+	}
+
+	data, err := CreateTemplateData(srcStruct, dstStruct, newGenerationConfig(WithDeepCopy()))
+
+	require.NoError(t, err)
+	mods := strings.Join(data.ModsToStruct, "")
+	assert.Equal(t, 1, strings.Count(mods, "append(src.Lines[:0:0], src.Lines...)"), mods)
+	assert.Contains(t, mods, "if len(__synthetic__Lines) != 0 {")
+}
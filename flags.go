@@ -0,0 +1,204 @@
+package structmorph
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GenerationFlags are the command line flags that affect the generated code, they are recorded in the header
+// of the generated file and parsed back when the file is regenerated.
+type GenerationFlags struct {
+	Src         string
+	Dst         string
+	Root        string
+	Flatten     bool
+	Match       string
+	TrimPrefix  string
+	TrimSuffix  string
+	Guard       bool
+	Methods     bool
+	Ptr         bool
+	Nested      bool
+	Collections bool
+	Patch       bool
+	DeepCopy    bool
+	Converter   bool
+	Group       string
+	Template    string
+}
+
+// Register defines the generation flags in the flag set.
+func (f *GenerationFlags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.Src, "src", "", "Source struct name")
+	fs.StringVar(&f.Dst, "dst", "", "Destination struct name")
+	fs.StringVar(&f.Root, "root", "", "Root directory")
+	fs.BoolVar(&f.Flatten, "flatten", false, "Bind destination fields to nested source fields by naming convention, e.g. AddressCity to Address.City")
+	fs.StringVar(&f.Match, "match", "", "Comma separated field matching strategies: ignore-case, initialism, tag:<key>")
+	fs.StringVar(&f.TrimPrefix, "trim-prefix", "", "Comma separated field name prefixes ignored when matching fields, e.g. Dto")
+	fs.StringVar(&f.TrimSuffix, "trim-suffix", "", "Comma separated field name suffixes ignored when matching fields")
+	fs.BoolVar(&f.Guard, "guard", false, "Add a compile-time guard that breaks the build when the source struct fields change")
+	fs.BoolVar(&f.Methods, "methods", false, "Generate the conversions as methods of the structs, or a constructor when the source struct is in another package")
+	fs.BoolVar(&f.Ptr, "ptr", false, "Generate the variants of the conversions that take and return the pointers, nil is converted to nil")
+	fs.BoolVar(&f.Nested, "nested", false, "Convert the nested struct fields of different types with the generated conversions of their struct pairs")
+	fs.BoolVar(&f.Collections, "collections", false, "Generate the slice and map variants of the conversions, nil is converted to nil")
+	fs.BoolVar(&f.Patch, "patch", false, "Generate the function applying the fields present in the destination struct to the source struct, the presence-tracking fields are left absent in the conversion to the destination struct")
+	fs.BoolVar(&f.DeepCopy, "deep-copy", false, "Copy the slices, maps and pointed-to values of the fields recursively, override it by the morphcopy:\"deep\" or morphcopy:\"shallow\" field tag")
+	fs.BoolVar(&f.Converter, "converter", false, "Declare the interface of the conversions and its zero-size implementation")
+	fs.StringVar(&f.Group, "converter-group", "", "Declare the converter and embed it into the group interface of the given name in the destination package")
+	fs.StringVar(&f.Template, "template", "", "Comma separated text/template files of the custom output template, the first one is executed")
+}
+
+// Options returns the generation options set by the flags.
+func (f *GenerationFlags) Options() ([]GenerationConfigOption, error) {
+	var opts []GenerationConfigOption
+	if f.Root != "" {
+		opts = append(opts, WithProjectRoot(f.Root))
+	}
+	if f.Flatten {
+		opts = append(opts, WithFlattening())
+	}
+	if f.Guard {
+		opts = append(opts, WithExhaustivenessGuard())
+	}
+	if f.Methods {
+		opts = append(opts, WithMethods())
+	}
+	if f.Ptr {
+		opts = append(opts, WithPtrVariants())
+	}
+	if f.Collections {
+		opts = append(opts, WithCollections())
+	}
+	if f.Patch {
+		opts = append(opts, WithPatch())
+	}
+	if f.DeepCopy {
+		opts = append(opts, WithDeepCopy())
+	}
+	if f.Nested {
+		opts = append(opts, WithFieldTransformers(NestedTransformer()))
+	}
+	if f.Converter {
+		opts = append(opts, WithConverter())
+	}
+	if f.Group != "" {
+		opts = append(opts, WithConverterGroup(f.Group))
+	}
+	if f.Match != "" {
+		for _, name := range strings.Split(f.Match, ",") {
+			matcher, err := ParseFieldMatcher(name)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithFieldMatchers(matcher))
+		}
+	}
+	if f.Template != "" {
+		opts = append(opts, WithTemplate(splitList(f.Template)...))
+	}
+	if f.TrimPrefix != "" || f.TrimSuffix != "" {
+		opts = append(opts, WithFieldMatchers(AffixMatcher(splitList(f.TrimPrefix), splitList(f.TrimSuffix))))
+	}
+	return opts, nil
+}
+
+// configArgs returns the flags setting the generation options of the config as the arguments recorded in the header
+// of the generated file, sorted by name, the options set by the flags are the same. The custom matchers and transformers
+// have no flags and are not recorded.
+func configArgs(cfg *GenerationConfig) []string {
+	var args []string
+	for _, option := range []struct {
+		flag string
+		set  bool
+	}{
+		{"flatten", cfg.Flatten},
+		{"guard", cfg.Guard},
+		{"methods", cfg.Methods},
+		{"ptr", cfg.Ptr},
+		{"collections", cfg.Collections},
+		{"patch", cfg.Patch},
+		{"deep-copy", cfg.DeepCopy},
+		{"converter", cfg.Converter && cfg.ConverterGroup == ""},
+	} {
+		if option.set {
+			args = append(args, "--"+option.flag)
+		}
+	}
+	if cfg.ConverterGroup != "" {
+		args = append(args, "--converter-group="+cfg.ConverterGroup)
+	}
+	if len(cfg.Templates) > 0 {
+		args = append(args, "--template="+strings.Join(cfg.Templates, ","))
+	}
+
+	var match, prefixes, suffixes []string
+	for _, matcher := range cfg.Matchers {
+		switch matcher := matcher.(type) {
+		case namedMatcher:
+			match = append(match, matcher.name)
+		case affixMatcher:
+			prefixes = append(prefixes, matcher.prefixes...)
+			suffixes = append(suffixes, matcher.suffixes...)
+		}
+	}
+	if len(match) > 0 {
+		args = append(args, "--match="+strings.Join(match, ","))
+	}
+	if len(prefixes) > 0 {
+		args = append(args, "--trim-prefix="+strings.Join(prefixes, ","))
+	}
+	if len(suffixes) > 0 {
+		args = append(args, "--trim-suffix="+strings.Join(suffixes, ","))
+	}
+	for _, transformer := range cfg.Transformers {
+		if _, ok := transformer.(nestedTransformer); ok {
+			args = append(args, "--nested")
+		}
+	}
+
+	sort.Strings(args)
+	return args
+}
+
+// FlagArgs returns the explicitly set flags of the flag set except the excluded ones as the arguments recorded
+// in the header of the generated file, sorted by name.
+func FlagArgs(fs *flag.FlagSet, exclude map[string]bool) []string {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		if exclude[f.Name] {
+			return
+		}
+		if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() && f.Value.String() == "true" {
+			args = append(args, "--"+f.Name)
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value))
+	})
+	return args
+}
+
+// resolvePaths returns the flags with the relative file paths resolved against the directory,
+// the recorded and annotated flags are relative to the directory of the generated file.
+func (f GenerationFlags) resolvePaths(dir string) GenerationFlags {
+	if f.Template == "" {
+		return f
+	}
+	files := splitList(f.Template)
+	for i, file := range files {
+		if !filepath.IsAbs(file) {
+			files[i] = filepath.Join(dir, file)
+		}
+	}
+	f.Template = strings.Join(files, ",")
+	return f
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
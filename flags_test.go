@@ -0,0 +1,62 @@
+package structmorph
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GenerationConfigOption
+		want []string
+	}{
+		{
+			name: "configArgs without options",
+		},
+		{
+			name: "configArgs with boolean options",
+			opts: []GenerationConfigOption{WithFlattening(), WithPtrVariants(), WithConverter(), WithFieldTransformers(NestedTransformer())},
+			want: []string{"--converter", "--flatten", "--nested", "--ptr"},
+		},
+		{
+			name: "configArgs with converter group",
+			opts: []GenerationConfigOption{WithConverter(), WithConverterGroup("Mapper")},
+			want: []string{"--converter-group=Mapper"},
+		},
+		{
+			name: "configArgs with matchers in order",
+			opts: []GenerationConfigOption{
+				WithFieldMatchers(TagMatcher("json"), CaseInsensitiveMatcher()),
+				WithFieldMatchers(AffixMatcher([]string{"Dto"}, []string{"Value"})),
+			},
+			want: []string{"--match=tag:json,ignore-case", "--trim-prefix=Dto", "--trim-suffix=Value"},
+		},
+		{
+			name: "configArgs without custom matchers and transformers",
+			opts: []GenerationConfigOption{
+				WithFieldMatchers(FieldMatcherFunc(func(SrcFieldType, DstFieldType) bool { return false })),
+				WithFieldTransformers(PointerTransformer()),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := configArgs(newGenerationConfig(tt.opts...))
+
+			assert.Equal(t, tt.want, got)
+
+			// the recorded flags set the same options
+			var flags GenerationFlags
+			fs := flag.NewFlagSet("structmorph", flag.ContinueOnError)
+			flags.Register(fs)
+			require.NoError(t, fs.Parse(got))
+			opts, err := flags.Options()
+			require.NoError(t, err)
+			assert.Equal(t, got, configArgs(newGenerationConfig(opts...)))
+		})
+	}
+}
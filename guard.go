@@ -0,0 +1,60 @@
+package structmorph
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// GuardField is a field of the source struct listed in the exhaustiveness guard.
+type GuardField struct {
+	// Decl is the field declaration as it is written in the struct type, e.g. `Name string` or `base.Model`.
+	Decl    string
+	Comment string
+}
+
+// CreateGuard lists every field of the source struct, marked as mapped or ignored, for the exhaustiveness guard.
+// The guard converts the source struct to the struct type with the listed fields, so the generated code stops
+// compiling as soon as a field is added to or removed from the source struct.
+func CreateGuard(srcStruct SrcStructType, dstStruct DstStructType, fields []FieldMapping) ([]GuardField, error) {
+	if srcStruct.GoType == nil {
+		return nil, fmt.Errorf("no type information for struct: %s", srcStruct.Name)
+	}
+	structType, ok := srcStruct.GoType.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("not a struct: %s", srcStruct.Name)
+	}
+
+	mapped := make(map[string][]string, len(fields))
+	for _, field := range fields {
+		root := srcRootName(field.SrcField)
+		mapped[root] = append(mapped[root], fmt.Sprintf("%s.%s", dstStruct.Name, field.DstField.Name))
+	}
+
+	qualifier := func(pkg *types.Package) string {
+		if pkg.Path() == dstStruct.ImportPath {
+			return ""
+		}
+		return pkg.Name()
+	}
+
+	guard := make([]GuardField, 0, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if !field.Exported() && srcStruct.ImportPath != dstStruct.ImportPath {
+			return nil, fmt.Errorf("cannot guard unexported field from another package, field: %s, struct: %s", field.Name(), srcStruct.Name)
+		}
+
+		decl := types.TypeString(field.Type(), qualifier)
+		if !field.Embedded() {
+			decl = fmt.Sprintf("%s %s", field.Name(), decl)
+		}
+		comment := "ignored"
+		if dstFields, ok := mapped[field.Name()]; ok {
+			comment = fmt.Sprintf("mapped to %s", strings.Join(dstFields, ", "))
+		}
+		guard = append(guard, GuardField{Decl: decl, Comment: comment})
+	}
+
+	return guard, nil
+}
@@ -0,0 +1,66 @@
+package structmorph
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGuard(t *testing.T) {
+	pkg := types.NewPackage("example.com/domain", "domain")
+	base := types.NewPackage("example.com/base", "base")
+	model := types.NewNamed(types.NewTypeName(token.NoPos, base, "Model", nil), types.NewStruct(nil, nil), nil)
+	person := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Person", nil), types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, pkg, "Name", types.Typ[types.String], false),
+		types.NewField(token.NoPos, pkg, "Tags", types.NewSlice(types.Typ[types.String]), false),
+		types.NewField(token.NoPos, pkg, "Model", model, true),
+	}, nil), nil)
+
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "domain", Name: "Person"},
+		ImportPath: "example.com/domain",
+		GoType:     person,
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "domain", Name: "PersonDTO"},
+		ImportPath: "example.com/domain",
+	}
+	fields := []FieldMapping{
+		{
+			SrcField: SrcFieldType{FieldType: FieldType{Name: "Name"}},
+			DstField: DstFieldType{FieldType: FieldType{Name: "FullName"}},
+		},
+	}
+
+	got, err := CreateGuard(srcStruct, dstStruct, fields)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []GuardField{
+		{Decl: "Name string", Comment: "mapped to PersonDTO.FullName"},
+		{Decl: "Tags []string", Comment: "ignored"},
+		{Decl: "base.Model", Comment: "ignored"},
+	}, got)
+}
+
+func TestCreateGuard__unexportedFieldFromAnotherPackage(t *testing.T) {
+	pkg := types.NewPackage("example.com/domain", "domain")
+	person := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Person", nil), types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, pkg, "secret", types.Typ[types.String], false),
+	}, nil), nil)
+
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "domain", Name: "Person"},
+		ImportPath: "example.com/domain",
+		GoType:     person,
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "api", Name: "PersonDTO"},
+		ImportPath: "example.com/api",
+	}
+
+	_, err := CreateGuard(srcStruct, dstStruct, nil)
+
+	assert.ErrorContains(t, err, "cannot guard unexported field from another package")
+}
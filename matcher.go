@@ -0,0 +1,145 @@
+package structmorph
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FieldMatcher decides whether the source field matches the destination field
+// when the destination field is bound neither by the exact name nor by the morph tag.
+type FieldMatcher interface {
+	MatchField(src SrcFieldType, dst DstFieldType) bool
+}
+
+// FieldMatcherFunc is an adapter to allow the use of ordinary functions as FieldMatcher.
+type FieldMatcherFunc func(src SrcFieldType, dst DstFieldType) bool
+
+func (f FieldMatcherFunc) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	return f(src, dst)
+}
+
+// namedMatcher is the built-in matcher together with its name accepted by ParseFieldMatcher,
+// so that the matcher is recorded in the header of the generated file.
+type namedMatcher struct {
+	FieldMatcherFunc
+	name string
+}
+
+// CaseInsensitiveMatcher matches the fields whose names are equal ignoring case, e.g. `Username` and `UserName`.
+func CaseInsensitiveMatcher() FieldMatcher {
+	return namedMatcher{name: "ignore-case", FieldMatcherFunc: func(src SrcFieldType, dst DstFieldType) bool {
+		return strings.EqualFold(src.Name, dst.Name)
+	}}
+}
+
+// InitialismMatcher matches the fields whose names consist of the same words regardless of the initialism style,
+// e.g. `UserID` and `UserId` or `HTTPServer` and `HttpServer`.
+func InitialismMatcher() FieldMatcher {
+	return namedMatcher{name: "initialism", FieldMatcherFunc: func(src SrcFieldType, dst DstFieldType) bool {
+		return normalizeInitialisms(src.Name) == normalizeInitialisms(dst.Name)
+	}}
+}
+
+// TagMatcher matches the fields that have the same non-empty name in the tag with the given key, e.g. `json:"name"`.
+func TagMatcher(key string) FieldMatcher {
+	return namedMatcher{name: "tag:" + key, FieldMatcherFunc: func(src SrcFieldType, dst DstFieldType) bool {
+		srcName := tagName(src.Tag.Get(key))
+		return srcName != "" && srcName != "-" && srcName == tagName(dst.Tag.Get(key))
+	}}
+}
+
+// AffixMatcher matches the fields whose names are equal after stripping any of the prefixes and suffixes,
+// e.g. `DtoName` and `Name` with the `Dto` prefix. The affix is stripped only on the word boundary.
+func AffixMatcher(prefixes, suffixes []string) FieldMatcher {
+	return affixMatcher{prefixes: prefixes, suffixes: suffixes}
+}
+
+type affixMatcher struct {
+	prefixes []string
+	suffixes []string
+}
+
+func (m affixMatcher) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	return stripAffixes(src.Name, m.prefixes, m.suffixes) == stripAffixes(dst.Name, m.prefixes, m.suffixes)
+}
+
+// ParseFieldMatcher returns the built-in matcher by its name: `ignore-case`, `initialism` or `tag:<key>`.
+func ParseFieldMatcher(name string) (FieldMatcher, error) {
+	switch name = strings.TrimSpace(name); {
+	case name == "ignore-case":
+		return CaseInsensitiveMatcher(), nil
+	case name == "initialism":
+		return InitialismMatcher(), nil
+	case strings.HasPrefix(name, "tag:") && len(name) > len("tag:"):
+		return TagMatcher(strings.TrimPrefix(name, "tag:")), nil
+	default:
+		return nil, fmt.Errorf("unknown field matcher: %s", name)
+	}
+}
+
+// matchSrcField returns the names of the source fields that match the destination field by the first matcher
+// that finds any, the names are sorted.
+func matchSrcField(srcStruct SrcStructType, dstField DstFieldType, matchers []FieldMatcher) []string {
+	for _, matcher := range matchers {
+		var names []string
+		for _, name := range srcStruct.SortedFieldNames() {
+			if matcher.MatchField(srcStruct.Fields[name], dstField) {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			return names
+		}
+	}
+	return nil
+}
+
+func normalizeInitialisms(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// splitWords splits the camel case name into words, a run of upper case letters is treated as one word,
+// e.g. `HTTPServerID` is split into `HTTP`, `Server` and `ID`.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prevUpper := unicode.IsUpper(runes[i-1])
+		currUpper := unicode.IsUpper(runes[i])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if currUpper && (!prevUpper || nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+func stripAffixes(name string, prefixes, suffixes []string) string {
+	for _, prefix := range prefixes {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if ok && rest != "" && unicode.IsUpper([]rune(rest)[0]) {
+			name = rest
+			break
+		}
+	}
+	for _, suffix := range suffixes {
+		rest, ok := strings.CutSuffix(name, suffix)
+		if ok && rest != "" && suffix != "" && unicode.IsUpper([]rune(suffix)[0]) {
+			name = rest
+			break
+		}
+	}
+	return name
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,107 @@
+package structmorph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldMatchers(t *testing.T) {
+	newSrc := func(name string, tag reflect.StructTag) SrcFieldType {
+		return SrcFieldType{FieldType: FieldType{Name: name, Tag: tag}}
+	}
+	newDst := func(name string, tag reflect.StructTag) DstFieldType {
+		return DstFieldType{FieldType: FieldType{Name: name, Tag: tag}, SrcField: name}
+	}
+
+	tests := []struct {
+		name    string
+		matcher FieldMatcher
+		src     SrcFieldType
+		dst     DstFieldType
+		want    bool
+	}{
+		{
+			name:    "CaseInsensitiveMatcher with different case",
+			matcher: CaseInsensitiveMatcher(),
+			src:     newSrc("Username", ""),
+			dst:     newDst("UserName", ""),
+			want:    true,
+		},
+		{
+			name:    "InitialismMatcher with different initialism style",
+			matcher: InitialismMatcher(),
+			src:     newSrc("HTTPServerID", ""),
+			dst:     newDst("HttpServerId", ""),
+			want:    true,
+		},
+		{
+			name:    "InitialismMatcher with different words",
+			matcher: InitialismMatcher(),
+			src:     newSrc("Username", ""),
+			dst:     newDst("UserName", ""),
+			want:    false,
+		},
+		{
+			name:    "TagMatcher with the same tag name",
+			matcher: TagMatcher("json"),
+			src:     newSrc("Email", `json:"email,omitempty"`),
+			dst:     newDst("Mail", `json:"email"`),
+			want:    true,
+		},
+		{
+			name:    "TagMatcher with skipped field",
+			matcher: TagMatcher("json"),
+			src:     newSrc("Email", `json:"-"`),
+			dst:     newDst("Mail", `json:"-"`),
+			want:    false,
+		},
+		{
+			name:    "AffixMatcher with prefix",
+			matcher: AffixMatcher([]string{"Dto"}, nil),
+			src:     newSrc("Name", ""),
+			dst:     newDst("DtoName", ""),
+			want:    true,
+		},
+		{
+			name:    "AffixMatcher with suffix",
+			matcher: AffixMatcher(nil, []string{"Value"}),
+			src:     newSrc("PriceValue", ""),
+			dst:     newDst("Price", ""),
+			want:    true,
+		},
+		{
+			name:    "AffixMatcher without word boundary",
+			matcher: AffixMatcher([]string{"Dto"}, nil),
+			src:     newSrc("Name", ""),
+			dst:     newDst("Dtoname", ""),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.matcher.MatchField(tt.src, tt.dst))
+		})
+	}
+}
+
+func TestCreateMapping__ambiguousMatch(t *testing.T) {
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "main", Name: "User"},
+		Fields: map[string]SrcFieldType{
+			"Username": {FieldType: FieldType{Name: "Username", Type: FieldTypeType{Name: "string"}}},
+			"USERNAME": {FieldType: FieldType{Name: "USERNAME", Type: FieldTypeType{Name: "string"}}},
+		},
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "main", Name: "UserDTO"},
+		Fields: []DstFieldType{
+			{FieldType: FieldType{Name: "UserName", Type: FieldTypeType{Name: "string"}}, SrcField: "UserName"},
+		},
+	}
+
+	_, err := CreateMapping(srcStruct, dstStruct, &GenerationConfig{Matchers: []FieldMatcher{CaseInsensitiveMatcher()}})
+
+	assert.ErrorContains(t, err, "candidates: USERNAME, Username")
+}
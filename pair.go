@@ -0,0 +1,151 @@
+package structmorph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// PairDecl is the struct pair declared by the type parameters of Pair.
+type PairDecl struct {
+	Src StructName
+	Dst StructName
+
+	opts []GenerationConfigOption
+	err  error
+}
+
+// Pair declares the struct pair for the generator program, the structs are referenced by the types,
+// so the pair follows the renames and is found by the usages of the types:
+//
+//	func main() {
+//		err := structmorph.GeneratePairs(".",
+//			structmorph.Pair[domain.Person, api.PersonDTO](structmorph.WithFlattening()),
+//		)
+//		...
+//	}
+//
+// The structs are looked up by the import paths of their packages among the packages loaded from the root.
+// The header of the generated file records the generator program followed by the pair and its options
+// as the structmorph flags, the custom matchers and transformers are declared only in the program.
+func Pair[Src, Dst any](opts ...GenerationConfigOption) PairDecl {
+	src, err := reflectStructName(reflect.TypeFor[Src]())
+	if err != nil {
+		return PairDecl{err: err}
+	}
+	dst, err := reflectStructName(reflect.TypeFor[Dst]())
+	if err != nil {
+		return PairDecl{err: err}
+	}
+	return PairDecl{Src: src, Dst: dst, opts: opts}
+}
+
+// RenderPairs renders the converters of the declared pairs in the order of the pairs. The packages under the root
+// are loaded once and shared between all the pairs. The generated files record the generator program instead of
+// the structmorph command line, so they are regenerated by running the program again.
+func RenderPairs(root string, pairs ...PairDecl) ([]GeneratedFile, error) {
+	rendered, err := renderPairs(root, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]GeneratedFile, 0, len(rendered))
+	for _, file := range rendered {
+		files = append(files, file.GeneratedFile)
+	}
+	return files, nil
+}
+
+// GeneratePairs renders the converters of the declared pairs and writes them, the files and the converter groups
+// next to them are written with the options of their pair.
+func GeneratePairs(root string, pairs ...PairDecl) error {
+	files, err := renderPairs(root, pairs)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = writeGenerated(file.GeneratedFile, file.cfg)
+		if err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		err = updateGroups([]string{file.Path}, file.cfg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pairFile is the file rendered for the pair together with the config of the pair.
+type pairFile struct {
+	GeneratedFile
+	cfg *GenerationConfig
+}
+
+func renderPairs(root string, pairs []PairDecl) ([]pairFile, error) {
+	parser := &Parser{ProjectRoot: root}
+	files := make([]pairFile, 0, len(pairs))
+	generatedBy := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		if pair.err != nil {
+			return nil, pair.err
+		}
+
+		cfg := pair.config()
+		file, err := render(parser, cfg, pair.Src, pair.Dst)
+		if err != nil {
+			return nil, fmt.Errorf("error generating %s: %w", pair.Dst.qualifiedName(), err)
+		}
+		err = checkOutputPath(generatedBy, file, pair.Dst)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pairFile{GeneratedFile: file, cfg: cfg})
+	}
+	return files, nil
+}
+
+// config returns the generation config of the pair. Unless the command is set by the options, the header records
+// the pair and its options as the structmorph flags after the generator program.
+func (p PairDecl) config() *GenerationConfig {
+	cfg := newGenerationConfig(append([]GenerationConfigOption{WithProgram(generatorProgram())}, p.opts...)...)
+	if len(cfg.Command) == 0 {
+		cfg.Command = append(configArgs(cfg), "--dst="+p.Dst.qualifiedName(), "--src="+p.Src.qualifiedName())
+		sort.Strings(cfg.Command)
+	}
+	return cfg
+}
+
+// reflectStructName returns the name of the named struct type with the import path of its package.
+func reflectStructName(t reflect.Type) (StructName, error) {
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return StructName{}, fmt.Errorf("pair type is not a named struct: %s", t)
+	}
+	if strings.Contains(t.Name(), "[") {
+		return StructName{}, fmt.Errorf("pair type is an instantiated generic struct: %s", t)
+	}
+	if t.PkgPath() == "main" {
+		return StructName{}, fmt.Errorf("pair type is declared in the main package: %s", t)
+	}
+	return StructName{
+		// the package name is resolved when the struct is found, the last path element is only a guess
+		Package: t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:],
+		Name:    t.Name(),
+		Path:    t.PkgPath(),
+	}, nil
+}
+
+// generatorProgram returns the command that runs the current program, e.g. `go run example.com/tools/gen`.
+func generatorProgram() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Path != "" {
+		return "go run " + info.Path
+	}
+	return filepath.Base(os.Args[0])
+}
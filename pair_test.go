@@ -0,0 +1,25 @@
+package structmorph
+
+import (
+	"path/filepath"
+	"structmorph/test/pairs/api"
+	"structmorph/test/pairs/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPairs__keepsConfigOfPair(t *testing.T) {
+	files, err := renderPairs(filepath.Join("test", "pairs"), []PairDecl{
+		Pair[domain.Person, api.PersonDTO](WithFlattening(), WithForce()),
+		Pair[domain.Address, api.AddressDTO](),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.True(t, files[0].cfg.Force, "the groups next to the file are written with the options of its pair")
+	assert.True(t, files[0].cfg.Flatten)
+	assert.False(t, files[1].cfg.Force)
+	assert.Contains(t, string(files[0].Content), "--flatten")
+}
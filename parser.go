@@ -1,11 +1,15 @@
 package structmorph
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/types"
 	"log"
 	"log/slog"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -20,16 +24,29 @@ type Parser struct {
 		pkgs       []*packages.Package
 		loadPkgErr error
 	}
+
+	specCache struct {
+		once  sync.Once
+		specs []MappingSpec
+		err   error
+	}
 }
 
+// ErrStructNotFound is returned when the struct is not declared in any of the loaded packages.
+var ErrStructNotFound = errors.New("struct not found")
+
+// ErrStructAmbiguous is returned when the struct is declared in several packages of its package name.
+var ErrStructAmbiguous = errors.New("struct is declared in several packages, qualify it by the import path")
+
 type ParseStructTypeFunc func(name StructName, pkg *packages.Package, spec *ast.TypeSpec)
 
-func (p *Parser) FindStruct(name StructName, parser ParseStructTypeFunc) error {
+// loadPackages loads all the packages under the project root once and returns them.
+func (p *Parser) loadPackages() ([]*packages.Package, error) {
 	cfg := &packages.Config{
 		Dir:  p.ProjectRoot,
 		Logf: log.Printf, //todo
 		//todo убрать потом то что не нужно
-		Mode: packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedCompiledGoFiles | packages.NeedDeps | packages.NeedImports,
+		Mode: packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedCompiledGoFiles | packages.NeedDeps | packages.NeedImports | packages.NeedTypesInfo,
 	}
 
 	p.pkgCache.once.Do(func() {
@@ -39,22 +56,36 @@ func (p *Parser) FindStruct(name StructName, parser ParseStructTypeFunc) error {
 		}
 		p.pkgCache.pkgs = pkgs
 	})
-	if p.pkgCache.loadPkgErr != nil {
-		return p.pkgCache.loadPkgErr
+	return p.pkgCache.pkgs, p.pkgCache.loadPkgErr
+}
+
+// FindStruct calls the parser for the struct declared in the package with the import path of the struct if it is set,
+// otherwise in the package with the package name of the struct. The structs of the same name declared in the other
+// packages loaded from the root are never picked up. It returns an error listing the import paths of the candidates
+// if the struct is found in several packages of the same name, the import path tells them apart.
+func (p *Parser) FindStruct(name StructName, parser ParseStructTypeFunc) error {
+	pkgs, err := p.loadPackages()
+	if err != nil {
+		return err
 	}
 
-	var found bool
-	for _, pkg := range p.pkgCache.pkgs {
+	type match struct {
+		pkg  *packages.Package
+		file *ast.File
+		spec *ast.TypeSpec
+	}
+	var matches []match
+	for _, pkg := range pkgs {
+		if !name.declaredIn(pkg) {
+			continue
+		}
 		for _, file := range pkg.Syntax {
 			ast.Inspect(file, func(n ast.Node) bool {
 				switch node := n.(type) {
 				case *ast.GenDecl:
 					for _, spec := range node.Specs {
 						if t, ok := spec.(*ast.TypeSpec); ok && t.Name.Name == name.Name {
-							filePath := pkg.Fset.Position(file.Pos()).Filename
-							parser(name, pkg, t)
-							found = true
-							slog.Info("Struct found in file", "struct", name, "file", filePath, "importPath", pkg.Types.Path())
+							matches = append(matches, match{pkg: pkg, file: file, spec: t})
 							return false
 						}
 					}
@@ -64,79 +95,95 @@ func (p *Parser) FindStruct(name StructName, parser ParseStructTypeFunc) error {
 		}
 	}
 
-	if !found {
-		return fmt.Errorf("struct not found")
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("%w: %s", ErrStructNotFound, name.qualifiedName())
+	case 1:
+	default:
+		paths := make([]string, 0, len(matches))
+		for _, m := range matches {
+			paths = append(paths, m.pkg.Types.Path())
+		}
+		return fmt.Errorf("%w: %s, candidates: %s", ErrStructAmbiguous, name.qualifiedName(), strings.Join(paths, ", "))
 	}
 
+	found := matches[0]
+	slog.Info("Struct found in file", "struct", name, "file", found.pkg.Fset.Position(found.file.Pos()).Filename, "importPath", found.pkg.Types.Path())
+	parser(name, found.pkg, found.spec)
 	return nil
 }
 
 func (p *Parser) FindAndParseStructDst(name StructName) (DstStructType, error) {
 	result := &DstStructType{StructName: name}
 	return *result, p.FindStruct(name, func(name StructName, pkg *packages.Package, spec *ast.TypeSpec) {
+		result.Package = pkg.Types.Name()
 		result.FilePath = filepath.Dir(pkg.Fset.Position(spec.Pos()).Filename)
-		result.extractFields(spec)
+		result.ImportPath = pkg.Types.Path()
+		result.pkg = pkg
+		result.extractFields(pkg, spec)
 	})
 }
 
 func (p *Parser) FindAndParseStructSrc(name StructName) (SrcStructType, error) {
 	result := &SrcStructType{StructName: name}
 	return *result, p.FindStruct(name, func(name StructName, pkg *packages.Package, spec *ast.TypeSpec) {
+		result.Package = pkg.Types.Name()
 		result.ImportPath = pkg.Types.Path()
-		result.extractFields(spec)
+		result.GoType = lookupStructType(pkg, spec)
+		result.extractFields(pkg, spec)
 	})
 }
 
-func (t *SrcStructType) extractFields(spec *ast.TypeSpec) {
+func (t *SrcStructType) extractFields(pkg *packages.Package, spec *ast.TypeSpec) {
 	list := spec.Type.(*ast.StructType).Fields.List
+	goTypes := lookupFieldTypes(pkg, spec)
 	fields := make(map[string]SrcFieldType, len(list))
 	for _, field := range list {
 		fieldType := parseFieldType(field.Type)
 
-		fieldName := fieldType.Name
+		fieldName := embeddedFieldName(fieldType.Name)
 		// handle anonymous struct fields
 		if len(field.Names) > 0 {
 			fieldName = field.Names[0].Name
 		}
+		fieldType.GoType = goTypes[fieldName]
 
 		fields[fieldName] = SrcFieldType{
-			FieldType{
+			FieldType: FieldType{
 				Name: fieldName,
 				Type: fieldType,
+				Tag:  parseFieldTag(field.Tag),
 			},
 		}
 	}
 	t.Fields = fields
 }
 
-func (s *DstStructType) extractFields(t *ast.TypeSpec) {
+func (s *DstStructType) extractFields(pkg *packages.Package, t *ast.TypeSpec) {
 	list := t.Type.(*ast.StructType).Fields.List
+	goTypes := lookupFieldTypes(pkg, t)
 	fields := make([]DstFieldType, 0, len(list))
 	for _, astField := range list {
 		fieldType := parseFieldType(astField.Type)
 
-		fieldName := fieldType.Name
+		fieldName := embeddedFieldName(fieldType.Name)
 		// handle anonymous struct fields
 		if len(astField.Names) > 0 {
 			fieldName = astField.Names[0].Name
 		}
+		fieldType.GoType = goTypes[fieldName]
 
 		field := DstFieldType{
 			FieldType: FieldType{
 				Name: fieldName,
 				Type: fieldType,
+				Tag:  parseFieldTag(astField.Tag),
 			},
 			SrcField: fieldName,
 		}
 
-		if astField.Tag != nil {
-			tag := astField.Tag.Value
-			if strings.HasPrefix(tag, "`morph:") {
-				tagValue := strings.Trim(tag, "`")
-				tagValue = strings.TrimPrefix(tagValue, "morph:\"")
-				tagValue = strings.TrimSuffix(tagValue, "\"")
-				field.SrcField = tagValue
-			}
+		if tagValue, ok := field.Tag.Lookup("morph"); ok {
+			field.SrcField = tagValue
 		}
 
 		//}
@@ -148,11 +195,69 @@ func (s *DstStructType) extractFields(t *ast.TypeSpec) {
 	s.Fields = fields
 }
 
+func parseFieldTag(tag *ast.BasicLit) reflect.StructTag {
+	if tag == nil {
+		return ""
+	}
+	value, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(value)
+}
+
+// lookupFieldTypes returns the type-checked types of the struct fields by field name.
+// For pointer fields the pointed-to type is returned, the same way parseFieldType strips the pointer.
+// It returns nil if the struct is not declared in the package scope (e.g. declared inside a function).
+func lookupFieldTypes(pkg *packages.Package, spec *ast.TypeSpec) map[string]types.Type {
+	goType := lookupStructType(pkg, spec)
+	if goType == nil {
+		return nil
+	}
+	structType, ok := goType.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]types.Type, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		fieldType := field.Type()
+		if ptr, ok := fieldType.(*types.Pointer); ok {
+			fieldType = ptr.Elem()
+		}
+		result[field.Name()] = fieldType
+	}
+	return result
+}
+
+// lookupStructType returns the type-checked type of the struct declared in the package scope or nil.
+func lookupStructType(pkg *packages.Package, spec *ast.TypeSpec) types.Type {
+	if pkg.Types == nil {
+		return nil
+	}
+	obj := pkg.Types.Scope().Lookup(spec.Name.Name)
+	if obj == nil {
+		return nil
+	}
+	return obj.Type()
+}
+
+// embeddedFieldName returns the implicit name of the embedded field,
+// which is the type name without the package qualifier and the type arguments.
+func embeddedFieldName(typeName string) string {
+	typeName, _, _ = strings.Cut(typeName, "[")
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
+
 func parseFieldType(field ast.Expr) FieldTypeType {
 	switch t := field.(type) {
 	case *ast.Ident:
 		return FieldTypeType{Name: t.Name}
 	case *ast.ArrayType:
+		if t.Len != nil {
+			return FieldTypeType{Name: fmt.Sprintf("[%s]%s", types.ExprString(t.Len), parseFieldType(t.Elt).Name)}
+		}
 		return FieldTypeType{Name: fmt.Sprintf("[]%s", parseFieldType(t.Elt).Name)} //todo limit recursion
 	case *ast.MapType:
 		return FieldTypeType{Name: fmt.Sprintf("map[%s]%s", parseFieldType(t.Key).Name, parseFieldType(t.Value).Name)}
@@ -161,6 +266,6 @@ func parseFieldType(field ast.Expr) FieldTypeType {
 	//case *ast.SelectorExpr:
 	//	return fmt.Sprintf("%s.%s", t.X, t.Sel)
 	default:
-		return FieldTypeType{Name: fmt.Sprintf("%T", t)}
+		return FieldTypeType{Name: types.ExprString(t)}
 	}
 }
@@ -0,0 +1,89 @@
+package structmorph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_FindStruct(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/person.go":    "package a\n\ntype Person struct {\n\tA string\n}\n",
+		"b/person.go":    "package b\n\ntype Person struct {\n\tB string\n}\n",
+		"v2/a/person.go": "package a\n\ntype Person struct {\n\tV2 string\n}\n",
+		"cmd/main.go":    "package main\n\ntype Person struct {\n\tMain string\n}\n\nfunc main() {}\n",
+	})
+	parser := &Parser{ProjectRoot: dir}
+
+	tests := []struct {
+		name       string
+		rawName    string
+		wantPath   string
+		wantFields []string
+		wantErr    error
+	}{
+		{
+			name:       "FindStruct by package name skips the struct of the same name in other packages",
+			rawName:    "b.Person",
+			wantPath:   "example.com/module/b",
+			wantFields: []string{"B"},
+		},
+		{
+			name:       "FindStruct by import path tells apart the packages of the same name",
+			rawName:    "example.com/module/v2/a.Person",
+			wantPath:   "example.com/module/v2/a",
+			wantFields: []string{"V2"},
+		},
+		{
+			name:       "FindStruct without package looks in the main package",
+			rawName:    "Person",
+			wantPath:   "example.com/module/cmd",
+			wantFields: []string{"Main"},
+		},
+		{
+			name:    "FindStruct in unknown package",
+			rawName: "c.Person",
+			wantErr: ErrStructNotFound,
+		},
+		{
+			name:    "FindStruct by package name shared by several packages",
+			rawName: "a.Person",
+			wantErr: ErrStructAmbiguous,
+		},
+		{
+			name:    "FindStruct by import path of another package",
+			rawName: "example.com/module/c.Person",
+			wantErr: ErrStructNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := ParseStructName(tt.rawName)
+			require.NoError(t, err)
+
+			got, err := parser.FindAndParseStructSrc(name)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.ImportPath)
+			assert.Equal(t, tt.wantFields, got.SortedFieldNames())
+		})
+	}
+}
+
+func TestParser_FindStruct__ambiguousListsCandidates(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/person.go":    "package a\n\ntype Person struct {\n\tA string\n}\n",
+		"v2/a/person.go": "package a\n\ntype Person struct {\n\tV2 string\n}\n",
+	})
+	parser := &Parser{ProjectRoot: dir}
+
+	_, err := parser.FindAndParseStructSrc(StructName{Package: "a", Name: "Person"})
+
+	assert.ErrorIs(t, err, ErrStructAmbiguous)
+	assert.ErrorContains(t, err, "candidates: example.com/module/a, example.com/module/v2/a")
+}
@@ -0,0 +1,152 @@
+package structmorph
+
+import (
+	"fmt"
+	"go/types"
+	"log/slog"
+)
+
+// presenceMethod is the method of the presence-tracking types, e.g. Optional[T], that returns the value
+// and whether it is present.
+const presenceMethod = "Get"
+
+// WithPatch generates the function applying the destination struct as a patch to the source struct,
+// e.g. ApplyPersonPatchDTO(dst *Person, src PersonPatchDTO), or the method ApplyToPerson of the destination struct
+// with the methods enabled. Only the fields that are present in the patch are assigned: the pointer, slice and map
+// fields that are not nil and the fields of the presence-tracking types with the Get() (T, bool) method that report
+// the value. The other fields of the patch can't be absent, they are not applied. The presence-tracking fields
+// are one-way: the conversion to the destination struct leaves them absent, the conversion to the source struct
+// reads their value.
+func WithPatch() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Patch = true
+	}
+}
+
+// transformers returns the user transformers followed by the transformer of the presence-tracking fields
+// if the patch is enabled.
+func (c *GenerationConfig) transformers() []FieldTransformer {
+	if !c.Patch {
+		return c.Transformers
+	}
+	return append(c.Transformers[:len(c.Transformers):len(c.Transformers)], presenceTransformer{})
+}
+
+// presenceTransformer reads the value of the presence-tracking destination field, the value can't be made present
+// in the conversion to the destination struct, so the field is left absent there.
+type presenceTransformer struct{}
+
+func (presenceTransformer) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	return isPresenceOf(dst.Type, src.Type)
+}
+
+func (presenceTransformer) TransformField(field FieldMapping) (FieldTransform, error) {
+	slog.Warn("Presence field is left absent in the conversion to the destination struct", "field", field.DstField.Name)
+	name := SyntheticName(field.DstField.Name)
+	return FieldTransform{
+		Kind:  KindPresence,
+		ToDTO: Conversion{Value: fmt.Sprintf("*new(%s)", field.typeName(field.DstField.Type))},
+		ToStruct: Conversion{
+			Mod:   fmt.Sprintf("\n%s, _ := src.%s.%s()\n", name, field.DstField.Name, presenceMethod),
+			Value: name,
+		},
+	}, nil
+}
+
+// createPatchMods renders the statements of the patch function, the fields are assigned in their declaration order.
+func createPatchMods(t *TemplateData) error {
+	for _, field := range t.Fields {
+		if field.IsOneWay() {
+			continue
+		}
+		data, ok := patchSet(field)
+		if !ok {
+			slog.Info("Field can't be absent in the patch, it is not applied", "field", field.DstField.Name)
+			continue
+		}
+
+		for _, parent := range field.SrcField.Parents {
+			if parent.Type.IsPointer {
+				data.Allocs = append(data.Allocs, nestedAlloc{Target: "dst." + parent.Name, Type: parent.Type.Name})
+			}
+		}
+		set, err := renderTemplate(tmplNestedSet, data)
+		if err != nil {
+			return fmt.Errorf("error rendering patch field: %w", err)
+		}
+		t.PatchMods = append(t.PatchMods, set)
+	}
+	return nil
+}
+
+// patchSet returns the assignment of the field present in the patch, it reports false if the field can't be absent.
+func patchSet(field FieldMapping) (nestedSetData, bool) {
+	data := nestedSetData{Target: "dst." + field.SrcField.Name}
+	if isPresenceOf(field.DstField.Type, field.SrcField.Type) {
+		data.Cond = fmt.Sprintf("v, ok := src.%s.%s(); ok", field.DstField.Name, presenceMethod)
+		data.Value = "v"
+		return data, true
+	}
+	if !field.DstField.Type.IsPointer {
+		if !isNilable(field.DstField.Type) || field.ModToStruct != "" && !isNestedSet(field) {
+			return data, false
+		}
+		// the non-nil slice or map replaces the value, nil leaves it untouched
+		data.Cond = fmt.Sprintf("src.%s != nil", field.DstField.Name)
+		data.Value = field.ValueToStruct()
+		return data, true
+	}
+
+	data.Cond = fmt.Sprintf("src.%s != nil", field.DstField.Name)
+	switch {
+	case field.Kind == KindRef:
+		data.Value = "*src." + field.DstField.Name
+	case field.ModToStruct == "" && field.SrcField.Type.IsPointer:
+		// the pointer is assigned as is or converted by an expression, e.g. of the nested struct
+		data.Value = field.ValueToStruct()
+	default:
+		return data, false
+	}
+	return data, true
+}
+
+// isNestedSet reports whether the mod of the field only sets its value into the nested struct of the source struct,
+// so the value is read without it.
+func isNestedSet(field FieldMapping) bool {
+	return len(field.SrcField.Parents) > 0 && (field.Kind == KindDirect || field.Kind == KindGuarded)
+}
+
+// isNilable reports whether the field is of the slice or map type, whose nil value marks it absent in the patch.
+func isNilable(fieldType FieldTypeType) bool {
+	if fieldType.GoType == nil {
+		return false
+	}
+	switch fieldType.GoType.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// isPresenceOf reports whether the type is the presence-tracking type of the value type, i.e. it has the
+// Get() (T, bool) method returning the value type.
+func isPresenceOf(presence, value FieldTypeType) bool {
+	if presence.GoType == nil || value.GoType == nil || presence.IsPointer {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(presence.GoType, true, nil, presenceMethod)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 2 || !types.Identical(sig.Results().At(1).Type(), types.Typ[types.Bool]) {
+		return false
+	}
+
+	valueType := value.GoType
+	if value.IsPointer {
+		valueType = types.NewPointer(valueType)
+	}
+	return types.Identical(sig.Results().At(0).Type(), valueType)
+}
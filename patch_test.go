@@ -0,0 +1,61 @@
+package structmorph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePatchMods(t *testing.T) {
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "main", Name: "Person"},
+		Fields: map[string]SrcFieldType{
+			"ID":   {FieldType: FieldType{Name: "ID", Type: FieldTypeType{Name: "string"}}},
+			"Name": {FieldType: FieldType{Name: "Name", Type: FieldTypeType{Name: "string"}}},
+		},
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "main", Name: "PersonPatch"},
+		Fields: []DstFieldType{
+			{FieldType: FieldType{Name: "ID", Type: FieldTypeType{Name: "string"}}, SrcField: "ID"},
+			{FieldType: FieldType{Name: "Name", Type: FieldTypeType{Name: "string", IsPointer: true}}, SrcField: "Name"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []GenerationConfigOption
+		wantFunc string
+		wantDecl string
+	}{
+		{
+			name:     "function",
+			opts:     []GenerationConfigOption{WithPatch()},
+			wantFunc: "ApplyPersonPatch",
+			wantDecl: "func ApplyPersonPatch(dst *Person, src PersonPatch) {",
+		},
+		{
+			name:     "method",
+			opts:     []GenerationConfigOption{WithPatch(), WithMethods()},
+			wantFunc: "ApplyToPerson",
+			wantDecl: "func (src PersonPatch) ApplyToPerson(dst *Person) {",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := CreateTemplateData(srcStruct, dstStruct, newGenerationConfig(tt.opts...))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantFunc, data.FuncNamePatch)
+			require.Len(t, data.PatchMods, 1, "the field that can't be absent is not applied")
+			assert.Contains(t, data.PatchMods[0], "if src.Name != nil {")
+			assert.Contains(t, data.PatchMods[0], "dst.Name = *src.Name")
+
+			code := &bytes.Buffer{}
+			require.NoError(t, data.GenerateCode(code))
+			assert.Contains(t, code.String(), tt.wantDecl)
+		})
+	}
+}
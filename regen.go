@@ -0,0 +1,295 @@
+package structmorph
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// recordedPair is the struct pair and the arguments recorded in the header of the generated file.
+type recordedPair struct {
+	args  []string
+	flags GenerationFlags
+	src   StructName
+	dst   StructName
+	// dir is the directory the relative paths in the flags are resolved against.
+	dir string
+}
+
+// RenderAll finds the files generated by structmorph under the root and renders them again from the command lines
+// recorded in their headers. The packages under the root are loaded once and shared between all the files,
+// the root recorded in the command line is ignored. The options are applied before the recorded ones.
+func RenderAll(root string, opts ...GenerationConfigOption) ([]GeneratedFile, error) {
+	files, _, err := renderAll(root, newGenerationConfig(opts...), opts)
+	return files, err
+}
+
+// Regenerate renders the generated files under the root again and writes them together with the converter groups.
+// With the pruning enabled the files whose struct pair no longer exists are removed.
+func Regenerate(root string, opts ...GenerationConfigOption) error {
+	cfg := newGenerationConfig(opts...)
+	files, orphans, err := renderAll(root, cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = writeGenerated(file, cfg)
+		if err != nil {
+			return err
+		}
+	}
+	err = removeOrphans(orphans, cfg)
+	if err != nil {
+		return err
+	}
+	return updateGroups(append(pathsOf(files), orphans...), cfg)
+}
+
+// Orphans returns the generated files under the root whose source or destination struct no longer exists,
+// sorted by path. The files without the recorded command line or generated by another program are never
+// considered orphaned.
+func Orphans(root string) ([]string, error) {
+	var orphans []string
+	err := walkRecordedPairs(&Parser{ProjectRoot: root}, root, func(parser *Parser, path string, pair recordedPair) error {
+		orphaned, err := parser.isOrphaned(pair)
+		if err != nil {
+			return fmt.Errorf("error checking %s: %w", path, err)
+		}
+		if orphaned {
+			orphans = append(orphans, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// Prune removes the orphaned generated files under the root and returns them.
+// The files modified by hand are not removed without the force option.
+func Prune(root string, opts ...GenerationConfigOption) ([]string, error) {
+	orphans, err := Orphans(root)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newGenerationConfig(opts...)
+	err = removeOrphans(orphans, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return orphans, updateGroups(orphans, cfg)
+}
+
+func renderAll(root string, cfg *GenerationConfig, opts []GenerationConfigOption) ([]GeneratedFile, []string, error) {
+	var files []GeneratedFile
+	var orphans []string
+	err := walkRecordedPairs(&Parser{ProjectRoot: root}, root, func(parser *Parser, path string, pair recordedPair) error {
+		orphaned, err := parser.isOrphaned(pair)
+		if err != nil {
+			return fmt.Errorf("error regenerating %s: %w", path, err)
+		}
+		if orphaned {
+			if !cfg.Prune {
+				return fmt.Errorf("struct pair of the generated file no longer exists, use prune to remove it: %s", path)
+			}
+			orphans = append(orphans, path)
+			return nil
+		}
+
+		file, err := rerender(parser, pair, opts)
+		if err != nil {
+			return fmt.Errorf("error regenerating %s: %w", path, err)
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return files, orphans, nil
+}
+
+func removeOrphans(orphans []string, cfg *GenerationConfig) error {
+	for _, path := range orphans {
+		if !cfg.Force {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("error reading orphaned file: %w", err)
+			}
+			if !VerifyChecksum(content) {
+				return fmt.Errorf("refusing to remove generated file modified by hand, use --force to remove it: %s", path)
+			}
+		}
+		err := os.Remove(path)
+		if err != nil {
+			return fmt.Errorf("error removing orphaned file: %w", err)
+		}
+		slog.Info("Removed orphaned generated file", "file", path)
+	}
+	return nil
+}
+
+// walkRecordedPairs loads the packages under the root once and calls the function for every generated file
+// with the structmorph command line recorded, the files without it or generated by another program are skipped.
+func walkRecordedPairs(parser *Parser, root string, fn func(parser *Parser, path string, pair recordedPair) error) error {
+	pkgs, err := parser.loadPackages()
+	if err != nil {
+		return err
+	}
+
+	return walkGeneratedFiles(root, func(path string, content []byte) error {
+		if _, ok := recordedGroup(content); ok {
+			// the group files are updated from the converters when the generated files are written
+			return nil
+		}
+		line, ok := recordedCommand(content)
+		if !ok {
+			slog.Warn("Generated file has no recorded command, skipping it", "file", path)
+			return nil
+		}
+		if !strings.HasPrefix(line, "structmorph ") {
+			slog.Warn("Generated file is generated by another program, run it to regenerate the file", "file", path, "command", line)
+			return nil
+		}
+
+		pair, err := readRecordedPair(pkgs, path, line)
+		if err != nil {
+			return fmt.Errorf("error reading recorded command of %s: %w", path, err)
+		}
+		return fn(parser, path, pair)
+	})
+}
+
+// readRecordedPair parses the recorded command line. The destination struct is looked up in the package of
+// the generated file and the source struct in the package it imports, so the structs with the same name
+// in other packages loaded from the root are never picked up.
+func readRecordedPair(pkgs []*packages.Package, path, line string) (recordedPair, error) {
+	args, err := ParseCommand(line)
+	if err != nil {
+		return recordedPair{}, err
+	}
+
+	pair := recordedPair{args: args, dir: filepath.Dir(path)}
+	flags := flag.NewFlagSet("structmorph", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	pair.flags.Register(flags)
+	err = flags.Parse(args)
+	if err != nil {
+		return recordedPair{}, err
+	}
+
+	pair.src, err = ParseStructName(pair.flags.Src)
+	if err != nil {
+		return recordedPair{}, err
+	}
+	pair.dst, err = ParseStructName(pair.flags.Dst)
+	if err != nil {
+		return recordedPair{}, err
+	}
+
+	pkg, err := packageOfFile(pkgs, path)
+	if err != nil {
+		return recordedPair{}, err
+	}
+	if pair.dst.Path == "" {
+		pair.dst.Path = pkg.Types.Path()
+	}
+	if pair.src.Path == "" {
+		pair.src.Path, err = importedPath(pkg, path, pair.src.Package)
+		if err != nil {
+			return recordedPair{}, err
+		}
+	}
+	return pair, nil
+}
+
+// rerender renders the generated file from its recorded command line.
+func rerender(parser *Parser, pair recordedPair, opts []GenerationConfigOption) (GeneratedFile, error) {
+	flags := pair.flags.resolvePaths(pair.dir)
+	recordedOpts, err := flags.Options()
+	if err != nil {
+		return GeneratedFile{}, fmt.Errorf("error parsing recorded command: %w", err)
+	}
+	cfg := newGenerationConfig(append(append(opts, recordedOpts...), WithCommand(pair.args...))...)
+
+	return render(parser, cfg, pair.src, pair.dst)
+}
+
+// isOrphaned reports whether the source or the destination struct of the pair no longer exists.
+func (p *Parser) isOrphaned(pair recordedPair) (bool, error) {
+	for _, name := range []StructName{pair.src, pair.dst} {
+		err := p.FindStruct(name, func(StructName, *packages.Package, *ast.TypeSpec) {})
+		if errors.Is(err, ErrStructNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// packageOfFile returns the loaded package the file belongs to.
+func packageOfFile(pkgs []*packages.Package, path string) (*packages.Package, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving path: %w", err)
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.GoFiles {
+			if file == absPath && pkg.Types != nil {
+				return pkg, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("package of the generated file is not loaded: %s", path)
+}
+
+// importedPath returns the import path of the package with the name imported by the file,
+// or the path of the package itself if the file does not import it.
+func importedPath(pkg *packages.Package, path, pkgName string) (string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		return "", fmt.Errorf("error parsing generated file: %w", err)
+	}
+	if importPath := importPathOf(pkg, file.Imports, pkgName); importPath != "" {
+		return importPath, nil
+	}
+	return pkg.Types.Path(), nil
+}
+
+// importPathOf returns the import path of the package with the name among the imports of the package file
+// or an empty string if it is not imported.
+func importPathOf(pkg *packages.Package, imports []*ast.ImportSpec, pkgName string) string {
+	for _, spec := range imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		imported, ok := pkg.Imports[importPath]
+		if !ok || imported.Types == nil {
+			continue
+		}
+		if spec.Name != nil && spec.Name.Name == pkgName || imported.Types.Name() == pkgName {
+			return importPath
+		}
+	}
+	return ""
+}
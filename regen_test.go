@@ -0,0 +1,88 @@
+package structmorph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule writes the files of a temporary module and returns its root.
+func writeModule(t *testing.T, files map[string]string) string {
+	// the temporary module is not a part of any workspace
+	t.Setenv("GOWORK", "off")
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/module\n\ngo 1.22\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func orphansModule(t *testing.T) string {
+	return writeModule(t, map[string]string{
+		"domain/user.go": "package domain\n\ntype User struct {\n\tName string\n}\n",
+		"dto/dto.go":     "package dto\n\ntype PersonDTO struct {\n\tName string\n}\n",
+		// the source struct was renamed from Person to User
+		"dto/morph_person.go": GeneratedHeader + "\n" + commandDirective + "structmorph --dst=dto.PersonDTO --src=domain.Person\n\n" +
+			"package dto\n\nimport \"example.com/module/domain\"\n\nfunc ConvertToPerson(src PersonDTO) domain.Person {\n\treturn domain.Person{}\n}\n",
+		"dto/morph_user.go": GeneratedHeader + "\n" + commandDirective + "structmorph --dst=dto.PersonDTO --src=domain.User\n\n" +
+			"package dto\n\nimport \"example.com/module/domain\"\n\nfunc ConvertToUser(src PersonDTO) domain.User {\n\treturn domain.User{}\n}\n",
+	})
+}
+
+func TestOrphans(t *testing.T) {
+	dir := orphansModule(t)
+
+	orphans, err := Orphans(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "dto", "morph_person.go")}, orphans)
+}
+
+func TestPrune(t *testing.T) {
+	dir := orphansModule(t)
+
+	removed, err := Prune(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "dto", "morph_person.go")}, removed)
+	assert.NoFileExists(t, filepath.Join(dir, "dto", "morph_person.go"))
+	assert.FileExists(t, filepath.Join(dir, "dto", "morph_user.go"))
+}
+
+func TestPrune__modifiedByHand(t *testing.T) {
+	dir := orphansModule(t)
+	path := filepath.Join(dir, "dto", "morph_person.go")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, AddChecksum(content), 0644))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("// hand edit\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = Prune(dir)
+	assert.ErrorContains(t, err, "modified by hand")
+	assert.FileExists(t, path)
+
+	_, err = Prune(dir, WithForce())
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+}
+
+func TestRegenerate__orphaned(t *testing.T) {
+	dir := orphansModule(t)
+
+	_, err := RenderAll(dir)
+	assert.ErrorContains(t, err, "no longer exists")
+
+	err = Regenerate(dir, WithPruning())
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "dto", "morph_person.go"))
+}
@@ -0,0 +1,133 @@
+package structmorph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/tools/imports"
+)
+
+// GeneratedFile is the formatted generated code together with the path of the file it is intended for.
+type GeneratedFile struct {
+	Path    string
+	Content []byte
+}
+
+// Render runs the generation in memory and returns the formatted code without writing it.
+// The code is type-checked together with the rest of the destination package, so the code that does not compile
+// is never returned.
+func Render(src, dst string, opts ...GenerationConfigOption) (GeneratedFile, error) {
+	cfg := newGenerationConfig(opts...)
+	if len(cfg.Command) == 0 {
+		cfg.Command = []string{"--dst=" + dst, "--src=" + src}
+	}
+
+	srcStructName, err := ParseStructName(src)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+	dstStructName, err := ParseStructName(dst)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+
+	return render(cfg.NewParser(), cfg, srcStructName, dstStructName)
+}
+
+// render runs the generation with the parser, so the packages loaded once are shared between the struct pairs.
+func render(parser *Parser, cfg *GenerationConfig, srcStructName, dstStructName StructName) (GeneratedFile, error) {
+	srcStruct, err := parser.FindAndParseStructSrc(srcStructName)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+	slog.Info("Found and parsed struct", slog.Any("struct", srcStruct))
+
+	dstStruct, err := parser.FindAndParseStructDst(dstStructName)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+	slog.Info("Found and parsed struct", slog.Any("struct", dstStruct))
+
+	spec, ok, err := parser.findSpec(srcStruct, dstStruct)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+	if ok {
+		slog.Info("Applying mapping spec", "pos", spec.Pos)
+		// the config is shared between the pairs, the spec applies only to this one
+		pairCfg := *cfg
+		pairCfg.FieldSpecs = append(cfg.FieldSpecs[:len(cfg.FieldSpecs):len(cfg.FieldSpecs)], spec.Fields...)
+		cfg = &pairCfg
+	}
+
+	data, err := CreateTemplateData(srcStruct, dstStruct, cfg)
+	if err != nil {
+		return GeneratedFile{}, fmt.Errorf("error creating template data: %w", err)
+	}
+
+	outputTmpl, err := cfg.template()
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+	buff := &bytes.Buffer{}
+	err = data.Execute(outputTmpl, buff)
+	if err != nil {
+		return GeneratedFile{}, fmt.Errorf("error generating code: %w", err)
+	}
+	if _, ok := recordedCommand(buff.Bytes()); !ok || !IsGenerated(buff.Bytes()) {
+		return GeneratedFile{}, fmt.Errorf("generated code must start with the header, use {{.Header}} in the template")
+	}
+
+	fileName := filepath.Join(dstStruct.Filepath(), srcStruct.FileName())
+	content, err := imports.Process(fileName, buff.Bytes(), nil)
+	if err != nil {
+		return GeneratedFile{}, fmt.Errorf("error formatting code: %w", err)
+	}
+	file := GeneratedFile{Path: fileName, Content: AddChecksum(content)}
+
+	for _, funcName := range data.freeFuncNames() {
+		if declaredIn := dstStruct.FindFuncDecl(funcName, file.Path); declaredIn != "" {
+			slog.Warn("Function with the same name as the generated converter already exists", "func", funcName, "file", declaredIn)
+		}
+	}
+
+	err = parser.TypeCheck(file, dstStruct, data)
+	if err != nil {
+		return GeneratedFile{}, err
+	}
+
+	return file, nil
+}
+
+// Check runs the generation in memory and compares the result with the file on disk.
+// It returns the unified diff between them, which is empty if the file is up to date. The filesystem is never modified.
+func Check(src, dst string, opts ...GenerationConfigOption) (string, error) {
+	generated, err := Render(src, dst, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := os.ReadFile(generated.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("error reading generated file: %w", err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(string(generated.Content)),
+		FromFile: generated.Path,
+		ToFile:   generated.Path + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating diff: %w", err)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,24 @@
+package structmorph
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender__specDoesNotLeakIntoSharedConfig(t *testing.T) {
+	parser := &Parser{ProjectRoot: filepath.Join("test", "specdsl")}
+	cfg := newGenerationConfig(WithCommand("--dst=specdsl.PersonDTO", "--src=domain.Person"))
+	src := StructName{Package: "domain", Name: "Person"}
+	dst := StructName{Package: "specdsl", Name: "PersonDTO"}
+
+	first, err := render(parser, cfg, src, dst)
+	require.NoError(t, err)
+	second, err := render(parser, cfg, src, dst)
+	require.NoError(t, err)
+
+	assert.Empty(t, cfg.FieldSpecs)
+	assert.Equal(t, string(first.Content), string(second.Content))
+}
@@ -0,0 +1,334 @@
+package structmorph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// SpecPackagePath is the import path of the package with the mapping spec DSL.
+const SpecPackagePath = "structmorph/spec"
+
+// MappingSpec is the mapping between the source and destination structs declared with the spec DSL.
+type MappingSpec struct {
+	Src    StructName
+	Dst    StructName
+	Fields []FieldSpec
+	// Pos is the position of the declaration, it is used in the error messages.
+	Pos string
+}
+
+// FieldSpec is the mapping of the destination field declared in the spec.
+type FieldSpec struct {
+	Dst string
+	// Src is the source field bound to the destination field, if empty, it is bound the same way as without the spec.
+	Src    string
+	Ignore bool
+	Conv   FieldConv
+	// Imports are the import paths of the packages referenced by the conversion.
+	Imports []string
+}
+
+// FieldConv is the custom conversion of the field, the expressions are qualified for the destination package.
+type FieldConv struct {
+	// Const is the constant expression the destination field is set to, the field is not converted back.
+	Const string
+	// ToDst and ToSrc are the converter functions applied to the field value,
+	// the field is not converted back if ToSrc is empty.
+	ToDst string
+	ToSrc string
+}
+
+func WithFieldSpecs(fields ...FieldSpec) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.FieldSpecs = append(cfg.FieldSpecs, fields...)
+	}
+}
+
+// FindSpecs returns the mapping specs declared in the loaded packages, sorted by the destination struct.
+func (p *Parser) FindSpecs() ([]MappingSpec, error) {
+	pkgs, err := p.loadPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	p.specCache.once.Do(func() {
+		for _, pkg := range pkgs {
+			specs, err := readSpecs(pkg)
+			if err != nil {
+				p.specCache.err = err
+				return
+			}
+			p.specCache.specs = append(p.specCache.specs, specs...)
+		}
+		sort.Slice(p.specCache.specs, func(i, j int) bool {
+			return p.specCache.specs[i].Dst.qualifiedName() < p.specCache.specs[j].Dst.qualifiedName()
+		})
+	})
+	return p.specCache.specs, p.specCache.err
+}
+
+// findSpec returns the mapping spec of the struct pair if it is declared in the loaded packages.
+func (p *Parser) findSpec(srcStruct SrcStructType, dstStruct DstStructType) (MappingSpec, bool, error) {
+	specs, err := p.FindSpecs()
+	if err != nil {
+		return MappingSpec{}, false, err
+	}
+
+	var found []MappingSpec
+	for _, spec := range specs {
+		if spec.Src.Path == srcStruct.ImportPath && spec.Src.Name == srcStruct.Name &&
+			spec.Dst.Path == dstStruct.ImportPath && spec.Dst.Name == dstStruct.Name {
+			found = append(found, spec)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return MappingSpec{}, false, nil
+	case 1:
+		return found[0], true, nil
+	default:
+		return MappingSpec{}, false, fmt.Errorf("several specs are declared for %s, %s and %s", found[0].Dst.qualifiedName(), found[0].Pos, found[1].Pos)
+	}
+}
+
+// readSpecs reads the spec.New calls assigned to the package-level variables of the package.
+func readSpecs(pkg *packages.Package) ([]MappingSpec, error) {
+	if pkg.Types == nil || pkg.TypesInfo == nil {
+		return nil, nil
+	}
+
+	var specs []MappingSpec
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				for _, value := range spec.(*ast.ValueSpec).Values {
+					call, ok := value.(*ast.CallExpr)
+					if !ok || specFuncName(pkg.TypesInfo, call) != "New" {
+						continue
+					}
+					mappingSpec, err := readSpec(pkg, call)
+					if err != nil {
+						return nil, fmt.Errorf("%s: error reading spec: %w", pkg.Fset.Position(call.Pos()), err)
+					}
+					specs = append(specs, mappingSpec)
+				}
+			}
+		}
+	}
+	return specs, nil
+}
+
+func readSpec(pkg *packages.Package, call *ast.CallExpr) (MappingSpec, error) {
+	inst := pkg.TypesInfo.Instances[calleeIdent(call.Fun)]
+	if inst.TypeArgs == nil || inst.TypeArgs.Len() != 2 {
+		return MappingSpec{}, fmt.Errorf("spec.New requires the source and destination struct type arguments")
+	}
+	src, err := specStructName(inst.TypeArgs.At(0))
+	if err != nil {
+		return MappingSpec{}, err
+	}
+	dst, err := specStructName(inst.TypeArgs.At(1))
+	if err != nil {
+		return MappingSpec{}, err
+	}
+
+	result := MappingSpec{Src: src, Dst: dst, Pos: pkg.Fset.Position(call.Pos()).String()}
+	reader := specReader{info: pkg.TypesInfo, dstPath: dst.Path}
+	for _, arg := range call.Args {
+		field, err := reader.readMapper(arg)
+		if err != nil {
+			return MappingSpec{}, err
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	return result, nil
+}
+
+func specStructName(goType types.Type) (StructName, error) {
+	named, ok := goType.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return StructName{}, fmt.Errorf("spec type argument is not a named struct: %s", goType)
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return StructName{}, fmt.Errorf("spec type argument is not a struct: %s", goType)
+	}
+	return StructName{
+		Package: named.Obj().Pkg().Name(),
+		Name:    named.Obj().Name(),
+		Path:    named.Obj().Pkg().Path(),
+	}, nil
+}
+
+// specReader reads the mappers of the spec, the expressions are qualified for the destination package.
+type specReader struct {
+	info    *types.Info
+	dstPath string
+	imports []string
+}
+
+func (r *specReader) readMapper(expr ast.Expr) (FieldSpec, error) {
+	call, ok := expr.(*ast.CallExpr)
+	name := ""
+	if ok {
+		name = specFuncName(r.info, call)
+	}
+	r.imports = nil
+
+	var field FieldSpec
+	var err error
+	switch name {
+	case "FromTo":
+		if len(call.Args) < 2 {
+			return FieldSpec{}, fmt.Errorf("spec.FromTo requires the source and destination fields")
+		}
+		field.Src, err = r.stringValue(call.Args[0])
+		if err != nil {
+			return FieldSpec{}, err
+		}
+		field.Dst, err = r.stringValue(call.Args[1])
+		if err != nil {
+			return FieldSpec{}, err
+		}
+		if len(call.Args) > 3 {
+			return FieldSpec{}, fmt.Errorf("spec.FromTo accepts a single option, field: %s", field.Dst)
+		}
+		if len(call.Args) == 3 {
+			field.Conv, err = r.readOption(call.Args[2])
+			if err != nil {
+				return FieldSpec{}, err
+			}
+		}
+	case "Ignore":
+		if len(call.Args) != 1 {
+			return FieldSpec{}, fmt.Errorf("spec.Ignore requires the destination field")
+		}
+		field.Dst, err = r.stringValue(call.Args[0])
+		field.Ignore = true
+	case "Const":
+		if len(call.Args) != 2 {
+			return FieldSpec{}, fmt.Errorf("spec.Const requires the destination field and the value")
+		}
+		field.Dst, err = r.stringValue(call.Args[0])
+		if err == nil {
+			field.Conv.Const, err = r.constExpr(call.Args[1])
+		}
+	default:
+		return FieldSpec{}, fmt.Errorf("spec mapper must be a call of spec.FromTo, spec.Ignore or spec.Const: %s", types.ExprString(expr))
+	}
+	if err != nil {
+		return FieldSpec{}, err
+	}
+
+	field.Imports = r.imports
+	return field, nil
+}
+
+func (r *specReader) readOption(expr ast.Expr) (FieldConv, error) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || specFuncName(r.info, call) != "With" || len(call.Args) != 2 {
+		return FieldConv{}, fmt.Errorf("spec field option must be a call of spec.With: %s", types.ExprString(expr))
+	}
+
+	toDst, err := r.funcRef(call.Args[0])
+	if err != nil {
+		return FieldConv{}, err
+	}
+	if toDst == "" {
+		return FieldConv{}, fmt.Errorf("spec.With requires the converter to the destination field")
+	}
+	toSrc, err := r.funcRef(call.Args[1])
+	if err != nil {
+		return FieldConv{}, err
+	}
+	return FieldConv{ToDst: toDst, ToSrc: toSrc}, nil
+}
+
+func (r *specReader) stringValue(expr ast.Expr) (string, error) {
+	value := r.info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", fmt.Errorf("field name must be a string constant: %s", types.ExprString(expr))
+	}
+	return constant.StringVal(value), nil
+}
+
+// funcRef returns the reference to the package-level function or an empty string for nil.
+func (r *specReader) funcRef(expr ast.Expr) (string, error) {
+	if r.info.Types[expr].IsNil() {
+		return "", nil
+	}
+	fn, ok := r.info.Uses[calleeIdent(expr)].(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return "", fmt.Errorf("converter must be a package-level function: %s", types.ExprString(expr))
+	}
+	return r.qualify(fn)
+}
+
+// constExpr returns the literal or the reference to the constant.
+func (r *specReader) constExpr(expr ast.Expr) (string, error) {
+	if r.info.Types[expr].Value == nil {
+		return "", fmt.Errorf("value must be a literal or a constant: %s", types.ExprString(expr))
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return e.Value, nil
+	case *ast.UnaryExpr:
+		if _, ok := e.X.(*ast.BasicLit); ok {
+			return types.ExprString(e), nil
+		}
+	case *ast.Ident, *ast.SelectorExpr:
+		if obj, ok := r.info.Uses[calleeIdent(e)].(*types.Const); ok {
+			return r.qualify(obj)
+		}
+	}
+	return "", fmt.Errorf("value must be a literal or a constant: %s", types.ExprString(expr))
+}
+
+// qualify returns the reference to the package-level object from the destination package.
+func (r *specReader) qualify(obj types.Object) (string, error) {
+	if obj.Pkg() == nil || obj.Pkg().Path() == r.dstPath {
+		return obj.Name(), nil
+	}
+	if !obj.Exported() {
+		return "", fmt.Errorf("%s is not exported from %s", obj.Name(), obj.Pkg().Path())
+	}
+	r.imports = append(r.imports, obj.Pkg().Path())
+	return obj.Pkg().Name() + "." + obj.Name(), nil
+}
+
+// specFuncName returns the name of the function of the spec package called by the call expression
+// or an empty string if another function is called.
+func specFuncName(info *types.Info, call *ast.CallExpr) string {
+	fn, ok := info.Uses[calleeIdent(call.Fun)].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != SpecPackagePath {
+		return ""
+	}
+	return fn.Name()
+}
+
+// calleeIdent returns the identifier of the function or the constant referenced by the expression,
+// with the package qualifier and the type arguments stripped.
+func calleeIdent(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(e.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(e.X)
+	case *ast.ParenExpr:
+		return calleeIdent(e.X)
+	}
+	return nil
+}
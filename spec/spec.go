@@ -0,0 +1,66 @@
+// Package spec declares the mapping between the source and destination structs in Go code, for the structs
+// that cannot be annotated, e.g. third-party or generated ones. The declarations are read by the generator
+// statically from the loaded packages and have no effect at runtime, the types are referenced by the type
+// arguments, so the spec follows the renames of the structs:
+//
+//	var _ = spec.New[domain.Person, PersonDTO](
+//		spec.FromTo("FIO", "FullName"),
+//		spec.FromTo("Age", "Age", spec.With(strconv.Itoa, nil)),
+//		spec.Ignore("Internal"),
+//		spec.Const("Version", 2),
+//	)
+//
+// The converters are generated for every spec by `structmorph ./...` and the spec is applied whenever
+// the converters of the same pair are generated, so it may be combined with the other ways of generation.
+package spec
+
+// Spec is the mapping between the source and destination structs.
+type Spec[Src, Dst any] struct {
+	Mappers []Mapper
+}
+
+// New declares the mapping between the source and destination structs. The destination fields without mappers
+// are bound to the source fields the same way as without the spec, i.e. by name, morph tags and matchers.
+// The call must be the value of a package-level variable to be found by the generator.
+func New[Src, Dst any](mappers ...Mapper) Spec[Src, Dst] {
+	return Spec[Src, Dst]{Mappers: mappers}
+}
+
+// Mapper is the mapping of a single destination field, it is created by the builder functions of the package.
+type Mapper struct {
+	Dst    string
+	Src    string
+	Ignore bool
+	Value  any
+	Opts   []FieldOption
+}
+
+// FromTo binds the destination field to the source field, the source field may be a dotted path to
+// the field of a nested struct, e.g. "Address.City". It overrides the morph and morphto tags of the fields.
+// At most one option is accepted, the generator rejects the mapper with several ones.
+func FromTo(src, dst string, opts ...FieldOption) Mapper {
+	return Mapper{Dst: dst, Src: src, Opts: opts}
+}
+
+// Ignore leaves the destination field unset, as well as the source field bound to it by name.
+func Ignore(dst string) Mapper {
+	return Mapper{Dst: dst, Ignore: true}
+}
+
+// Const sets the destination field to the value, which must be a literal or a constant.
+// The source struct is not affected by the field.
+func Const(dst string, value any) Mapper {
+	return Mapper{Dst: dst, Value: value}
+}
+
+// FieldOption customizes the mapping of the field.
+type FieldOption struct {
+	ToDst any
+	ToSrc any
+}
+
+// With converts the field with the package-level functions, toDst is applied to the source field value and toSrc
+// to the destination field value. If toSrc is nil, the source field is left unset by the reverse conversion.
+func With[S, D any](toDst func(S) D, toSrc func(D) S) FieldOption {
+	return FieldOption{ToDst: toDst, ToSrc: toSrc}
+}
@@ -0,0 +1,57 @@
+package structmorph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mappers string
+		want    []FieldSpec
+		wantErr string
+	}{
+		{
+			name:    "FindSpecs with converter",
+			mappers: `spec.FromTo("Age", "Years", spec.With(strconv.Itoa, nil))`,
+			want:    []FieldSpec{{Dst: "Years", Src: "Age", Conv: FieldConv{ToDst: "strconv.Itoa"}, Imports: []string{"strconv"}}},
+		},
+		{
+			name:    "FindSpecs with several options of the field",
+			mappers: `spec.FromTo("Age", "Years", spec.With(strconv.Itoa, nil), spec.With(strconv.Quote, nil))`,
+			wantErr: "spec.FromTo accepts a single option, field: Years",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := specModule(t, "package dto\n\nimport (\n\t\"strconv\"\n\n\t\"structmorph/spec\"\n)\n\n"+
+				"type Person struct {\n\tAge int\n}\n\ntype PersonDTO struct {\n\tYears string\n}\n\n"+
+				"var _ = spec.New[Person, PersonDTO](\n\t"+tt.mappers+",\n)\n")
+
+			specs, err := (&Parser{ProjectRoot: dir}).FindSpecs()
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, specs, 1)
+			assert.Equal(t, tt.want, specs[0].Fields)
+		})
+	}
+}
+
+// specModule writes the temporary module with the file of the dto package importing the spec package of this module.
+func specModule(t *testing.T, dto string) string {
+	root, err := filepath.Abs(".")
+	require.NoError(t, err)
+	dir := writeModule(t, map[string]string{"dto/dto.go": dto})
+	goMod := "module example.com/module\n\ngo 1.22\n\nrequire structmorph v0.0.0\n\nreplace structmorph => " + root + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644))
+	return dir
+}
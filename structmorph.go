@@ -2,19 +2,65 @@ package structmorph
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"text/template"
+	"unicode"
 
-	"golang.org/x/tools/imports"
+	"golang.org/x/tools/go/packages"
 )
 
 type GenerationConfig struct {
 	ProjectRoot string
+	// Flatten enables binding of the destination fields to the nested source fields by naming convention,
+	// e.g. AddressCity is bound to Address.City.
+	Flatten bool
+	// Matchers are tried in order for the destination fields not found in the source struct by name.
+	Matchers []FieldMatcher
+	// Guard adds the compile-time exhaustiveness guard of the source struct fields to the generated code.
+	Guard bool
+	// Methods generates the conversions as methods of the converted structs instead of the free functions,
+	// the conversion of the source struct from another package is generated as a constructor of the destination.
+	Methods bool
+	// Ptr generates the variants of the conversions that take and return the pointers, nil is converted to nil.
+	Ptr bool
+	// Collections generates the slice and map variants of the conversions, nil is converted to nil.
+	Collections bool
+	// Patch generates the function applying the destination struct as a patch to the source struct.
+	Patch bool
+	// DeepCopy copies the slices, maps and pointed-to values of the fields recursively instead of sharing them.
+	DeepCopy bool
+	// Converter declares the interface of the conversions and its zero-size implementation,
+	// ConverterGroup adds the interface to the group interface of the destination package.
+	Converter      bool
+	ConverterGroup string
+	// Force allows overwriting of the files that were not generated by structmorph or were modified by hand.
+	Force bool
+	// Prune removes the generated files whose struct pair no longer exists during the regeneration,
+	// otherwise such files fail the regeneration.
+	Prune bool
+	// FieldSpecs are the mappings of the destination fields that take precedence over the morph tags and matchers,
+	// the spec declared for the struct pair in the loaded packages is added to them.
+	FieldSpecs []FieldSpec
+	// Command is the list of the arguments recorded in the header of the generated file.
+	Command []string
+	// Program is the program recorded in the header of the generated file before the arguments.
+	Program string
+	// Transformers convert the values of the field pairs they match, they are tried before the built-in ones.
+	Transformers []FieldTransformer
+	// Templates are the files of the custom output template, the first file is executed with the TemplateData.
+	// The built-in template is used if empty.
+	Templates []string
 }
 
 func (c *GenerationConfig) NewParser() *Parser {
@@ -26,9 +72,18 @@ func (c *GenerationConfig) NewParser() *Parser {
 func DefaultGenerationConfig() *GenerationConfig {
 	return &GenerationConfig{
 		ProjectRoot: ".",
+		Program:     "structmorph",
 	}
 }
 
+func newGenerationConfig(opts ...GenerationConfigOption) *GenerationConfig {
+	cfg := DefaultGenerationConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
 type GenerationConfigOption func(*GenerationConfig)
 
 func WithProjectRoot(root string) GenerationConfigOption {
@@ -37,82 +92,251 @@ func WithProjectRoot(root string) GenerationConfigOption {
 	}
 }
 
-func Generate(src, dst string, opts ...GenerationConfigOption) error {
-	cfg := DefaultGenerationConfig()
-	for _, opt := range opts {
-		opt(cfg)
+func WithFlattening() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Flatten = true
 	}
+}
 
-	parser := cfg.NewParser()
+func WithFieldMatchers(matchers ...FieldMatcher) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Matchers = append(cfg.Matchers, matchers...)
+	}
+}
 
-	srcStructName, err := ParseStructName(src)
-	if err != nil {
-		return err
+func WithExhaustivenessGuard() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Guard = true
+	}
+}
+
+// WithMethods generates the conversions as methods, e.g. Person.ToPersonDTO and PersonDTO.ToPerson. Methods can only
+// be declared in the package of their receiver, so when the source struct lives in another package than the destination
+// one the conversion to the destination is generated as a constructor instead, e.g. PersonDTOFromPerson.
+func WithMethods() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Methods = true
+	}
+}
+
+// WithPtrVariants generates the variants of the conversions with the Ptr suffix that take and return the pointers,
+// e.g. ConvertToPersonDTOPtr(src *Person) *PersonDTO, nil is converted to nil.
+func WithPtrVariants() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Ptr = true
+	}
+}
+
+// WithCollections generates the variants of the conversions for the slices and maps of the structs,
+// e.g. ConvertToPersonDTOs([]Person) []PersonDTO and ConvertToPersonDTOMap(map[K]Person) map[K]PersonDTO,
+// the slices of the pointers are converted too if the pointer variants are enabled.
+func WithCollections() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Collections = true
+	}
+}
+
+func WithForce() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Force = true
+	}
+}
+
+func WithPruning() GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Prune = true
+	}
+}
+
+// WithCommand sets the arguments of the command line recorded in the header of the generated file,
+// by default only the source and destination structs are recorded.
+func WithCommand(args ...string) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Command = args
+	}
+}
+
+// WithProgram sets the program recorded in the header of the generated file, e.g. the generator program
+// that declares the pairs, the files generated by other programs than structmorph are not regenerated by it.
+func WithProgram(program string) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Program = program
+	}
+}
+
+// WithTemplate sets the files of the custom output template in the text/template format, the first file is executed
+// and may use the templates defined in the other ones. The template receives the TemplateData and must start with
+// its Header, so that the generated file is recognized by structmorph.
+func WithTemplate(files ...string) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Templates = append(cfg.Templates, files...)
 	}
-	dstStructName, err := ParseStructName(dst)
+}
+
+// template returns the output template, the built-in one unless the custom template files are set.
+func (c *GenerationConfig) template() (*template.Template, error) {
+	if len(c.Templates) == 0 {
+		return tmpl, nil
+	}
+	custom, err := template.ParseFiles(c.Templates...)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("error parsing template: %w", err)
 	}
+	return custom, nil
+}
 
-	srcStruct, err := parser.FindAndParseStructSrc(srcStructName)
+func Generate(src, dst string, opts ...GenerationConfigOption) error {
+	cfg := newGenerationConfig(opts...)
+	file, err := Render(src, dst, opts...)
 	if err != nil {
 		return err
 	}
-	slog.Info("Found and parsed struct", slog.Any("struct", srcStruct))
 
-	dstStruct, err := parser.FindAndParseStructDst(dstStructName)
+	err = writeGenerated(file, cfg)
 	if err != nil {
 		return err
 	}
-	slog.Info("Found and parsed struct", slog.Any("struct", dstStruct))
+	return updateGroups([]string{file.Path}, cfg)
+}
 
-	data, err := CreateTemplateData(srcStruct, dstStruct)
-	if err != nil {
-		return fmt.Errorf("error creating template data: %w", err)
+// writeGenerated writes the generated file unless it would overwrite a file not generated by structmorph.
+func writeGenerated(file GeneratedFile, cfg *GenerationConfig) error {
+	if !cfg.Force {
+		err := checkOverwrite(file.Path)
+		if err != nil {
+			return err
+		}
 	}
 
-	buff := &bytes.Buffer{}
-	err = data.GenerateCode(buff)
+	err := os.WriteFile(file.Path, file.Content, 0644)
 	if err != nil {
-		return fmt.Errorf("error generating code: %w", err)
+		return fmt.Errorf("error writing generated code: %w", err)
 	}
 
-	fileName := filepath.Join(dstStruct.Filepath(), srcStruct.FileName())
-	err = FormatAndWrite(buff, fileName)
+	slog.Info("Generated and formatted code", "file", file.Path)
+	return nil
+}
+
+// GeneratedHeader marks the files generated by structmorph, only such files are overwritten without --force.
+const GeneratedHeader = "// Code generated by structmorph; DO NOT EDIT."
+
+// checkOverwrite returns an error if the file exists and was not generated by structmorph.
+func checkOverwrite(fileName string) error {
+	content, err := os.ReadFile(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("error formatting and writing code: %w", err)
+		return fmt.Errorf("error reading existing file: %w", err)
 	}
 
-	slog.Info("Generated and formatted code", "file", fileName)
+	if !IsGenerated(content) {
+		return fmt.Errorf("refusing to overwrite file not generated by structmorph, use --force to overwrite it: %s", fileName)
+	}
+	if !VerifyChecksum(content) {
+		return fmt.Errorf("refusing to overwrite generated file modified by hand, use --force to overwrite it: %s", fileName)
+	}
 	return nil
 }
 
-func CreateTemplateData(srcStruct SrcStructType, dstStruct DstStructType) (TemplateData, error) {
+// IsGenerated reports whether the source has the structmorph header before the package clause.
+func IsGenerated(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == GeneratedHeader {
+			return true
+		}
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+	}
+	return false
+}
+
+func CreateTemplateData(srcStruct SrcStructType, dstStruct DstStructType, cfg *GenerationConfig) (TemplateData, error) {
 	data := TemplateData{
 		FuncNameToDTO:    fmt.Sprintf("ConvertTo%s", dstStruct.Name),
 		FuncNameToStruct: fmt.Sprintf("ConvertTo%s", srcStruct.Name),
 		SrcStructName:    srcStruct.Name,
 		DstStructName:    dstStruct.Name,
 		DistFilePkgName:  dstStruct.Package,
+		Command:          formatCommand(cfg.Program, cfg.Command),
+	}
+	data.Header = GeneratedHeader + "\n" + commandDirective + data.Command
+	if cfg.Methods {
+		setMethodNames(&data, srcStruct, dstStruct)
 	}
 
 	data.SrcStructName = resolveSrcStructName(srcStruct, dstStruct)
 	data.SrcPkgPathImport = resolveSrcStructImport(srcStruct, dstStruct)
 
-	fields, err := CreateMapping(srcStruct, dstStruct)
+	fields, err := CreateMapping(srcStruct, dstStruct, cfg)
 	if err != nil {
 		return data, err
 	}
+	if cfg.Ptr {
+		data.FuncNameToDTOPtr = data.FuncNameToDTO + "Ptr"
+		data.FuncNameToStructPtr = data.FuncNameToStruct + "Ptr"
+	}
+	if cfg.Collections {
+		data.Collections = data.collectionFuncs()
+	}
+	if cfg.Patch {
+		data.FuncNamePatch = "Apply" + dstStruct.Name
+		if data.MethodToStruct {
+			data.FuncNamePatch = "ApplyTo" + srcStruct.Name
+		}
+	}
+	if cfg.Converter {
+		data.Converter, err = createConverter(srcStruct, dstStruct, cfg.ConverterGroup)
+		if err != nil {
+			return data, err
+		}
+	}
+
 	data.Fields = fields
+	data.Imports = specImports(cfg.FieldSpecs, data.SrcPkgPathImport)
+	data.cloner = newCloner(lowerFirst(srcStruct.Name)+"Copy", dstStruct.ImportPath, cfg.DeepCopy)
 
-	err = CreateMods(&data)
+	err = CreateMods(&data, cfg.transformers()...)
 	if err != nil {
 		return data, fmt.Errorf("error creating mods: %w", err)
 	}
+	data.CopyFuncs = data.cloner.funcs
+	data.Imports = mergeImports(data.Imports, data.cloner.sortedImports(), data.SrcPkgPathImport)
+	if cfg.Patch {
+		err = createPatchMods(&data)
+		if err != nil {
+			return data, fmt.Errorf("error creating patch: %w", err)
+		}
+	}
+
+	if cfg.Guard {
+		data.Guard, err = CreateGuard(srcStruct, dstStruct, fields)
+		if err != nil {
+			return data, fmt.Errorf("error creating exhaustiveness guard: %w", err)
+		}
+	}
 
 	return data, nil
 }
 
+// specImports returns the sorted import paths referenced by the field specs except the source package.
+func specImports(specs []FieldSpec, srcImport string) []string {
+	seen := map[string]bool{srcImport: true}
+	var result []string
+	for _, spec := range specs {
+		for _, path := range spec.Imports {
+			if !seen[path] {
+				seen[path] = true
+				result = append(result, path)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 func resolveSrcStructName(srcStruct SrcStructType, dstStruct DstStructType) string {
 	if srcStruct.Package == dstStruct.Package {
 		return srcStruct.Name
@@ -130,14 +354,49 @@ func resolveSrcStructImport(srcStruct SrcStructType, dstStruct DstStructType) st
 type StructName struct {
 	Package string
 	Name    string
+	// Path is the import path of the package, if set the struct is looked up only in that package.
+	Path string
+}
+
+// qualifiedName returns the name of the struct qualified by the import path if known or by the package name.
+func (n StructName) qualifiedName() string {
+	if n.Path != "" {
+		return n.Path + "." + n.Name
+	}
+	return n.Package + "." + n.Name
 }
 
+// declaredIn reports whether the struct may be declared in the package.
+func (n StructName) declaredIn(pkg *packages.Package) bool {
+	if pkg.Types == nil {
+		return false
+	}
+	if n.Path != "" {
+		return pkg.Types.Path() == n.Path
+	}
+	return pkg.Types.Name() == n.Package
+}
+
+// ParseStructName parses the struct name in the form `StructName`, `package.StructName`
+// or `import/path.StructName`.
 func ParseStructName(rawName string) (StructName, error) {
 	rawName = strings.TrimSpace(rawName)
 	if rawName == "" {
 		return StructName{}, fmt.Errorf("empty input")
 	}
 
+	if slash := strings.LastIndex(rawName, "/"); slash >= 0 {
+		dot := strings.LastIndex(rawName, ".")
+		if dot < slash || dot == len(rawName)-1 {
+			return StructName{}, fmt.Errorf("invalid format for struct name. Expected 'import/path.StructName'")
+		}
+		return StructName{
+			Package: rawName[slash+1 : dot],
+			Name:    rawName[dot+1:],
+			Path:    rawName[:dot],
+		}, nil
+	}
+
 	parts := strings.Split(rawName, ".")
 	if len(parts) == 1 {
 		return StructName{
@@ -157,18 +416,36 @@ func ParseStructName(rawName string) (StructName, error) {
 
 type DstStructType struct {
 	StructName
-	Fields   []DstFieldType
-	FilePath string
+	ImportPath string
+	Fields     []DstFieldType
+	FilePath   string
+
+	pkg *packages.Package
 }
 
 func (s *DstStructType) Filepath() string {
 	return s.FilePath
 }
 
+func (s *DstStructType) HasField(name string) bool {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 type SrcStructType struct {
 	StructName
 	ImportPath string
 	Fields     map[string]SrcFieldType
+	// GoType is the type-checked type of the struct, it may be nil if the type information is not available.
+	GoType types.Type
+
+	// dstImportPath is the import path of the package the conversions are generated into, the types of the fields
+	// referenced by dotted paths are qualified for it. The source package is used if it is empty.
+	dstImportPath string
 }
 
 type DstFieldType struct {
@@ -178,109 +455,671 @@ type DstFieldType struct {
 
 type SrcFieldType struct {
 	FieldType
+	// Parents is the chain of struct fields through which the field is reached when it is referenced by a dotted path,
+	// e.g. for "Address.City" it contains the "Address" field. The Name of every parent is its full path.
+	Parents []FieldType
+}
+
+// srcMorphTag is the struct tag of the source field naming the destination field it is mapped to, e.g. `morphto:"TeamSize"`.
+// The morph tag can't be used for it, since it names the source field when the struct is the destination of another pair.
+// The tag is ignored for the destination structs without the named field, so the source struct may be converted to several of them.
+const srcMorphTag = "morphto"
+
+// MorphTags returns the source field names by the destination field names declared in their morphto tags.
+// It returns an error if several source fields refer to the same destination field.
+func (t SrcStructType) MorphTags() (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, name := range t.SortedFieldNames() {
+		dstName, ok := t.Fields[name].Tag.Lookup(srcMorphTag)
+		if !ok {
+			continue
+		}
+		if other, ok := tags[dstName]; ok {
+			return nil, fmt.Errorf("several source fields refer to the same destination field, field: %s, struct: %s, candidates: %s, %s", dstName, t.Name, other, name)
+		}
+		tags[dstName] = name
+	}
+	return tags, nil
+}
+
+// SortedFieldNames returns the names of the top-level fields in the alphabetical order.
+func (t SrcStructType) SortedFieldNames() []string {
+	names := make([]string, 0, len(t.Fields))
+	for name := range t.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupField returns the source field by its name or by a dotted path to a field of a nested struct, e.g. "Address.City".
+// Fields promoted from embedded structs are resolved by the Go promotion rules and are returned
+// with the embedded structs as parents, e.g. "CompositeField" resolves to "CompositeType.CompositeField".
+func (t SrcStructType) LookupField(path string) (SrcFieldType, error) {
+	names := strings.Split(path, ".")
+	field, ok := t.Fields[names[0]]
+	var promoted []*types.Var
+	if !ok {
+		chain, err := t.selectField(t.GoType, names[0])
+		if err != nil {
+			return SrcFieldType{}, fmt.Errorf("%w, field: %s, struct: %s", err, path, t.Name)
+		}
+		field, promoted = t.Fields[chain[0].Name()], chain[1:]
+	}
+
+	var parents []FieldType
+	appendChain := func(chain []*types.Var) {
+		for _, next := range chain {
+			parent := field.FieldType
+			if parent.Type.GoType != nil {
+				// the parents are declared and allocated in the generated code
+				parent.Type.Name = t.typeName(parent.Type.GoType)
+			}
+			parents = append(parents, parent)
+			field = SrcFieldType{
+				FieldType: FieldType{
+					Name: field.Name + "." + next.Name(),
+					Type: t.fieldTypeOf(next.Type()),
+				},
+			}
+		}
+	}
+
+	appendChain(promoted)
+	for _, name := range names[1:] {
+		chain, err := t.selectField(field.Type.GoType, name)
+		if err != nil {
+			return SrcFieldType{}, fmt.Errorf("%w, field: %s, struct: %s", err, path, t.Name)
+		}
+		appendChain(chain)
+	}
+	field.Parents = parents
+
+	return field, nil
+}
+
+// selectField resolves the selector of the struct field the same way the compiler does and returns the chain
+// of the fields from the owner to the selected one, the chain contains more than one field for promoted fields.
+func (t SrcStructType) selectField(owner types.Type, name string) ([]*types.Var, error) {
+	if owner == nil {
+		return nil, errors.New("field not found")
+	}
+	if _, ok := owner.Underlying().(*types.Struct); !ok {
+		return nil, errors.New("field is not a struct")
+	}
+
+	obj, index, _ := types.LookupFieldOrMethod(owner, false, t.typesPkg(), name)
+	if _, ok := obj.(*types.Var); !ok {
+		if obj == nil && index != nil {
+			return nil, fmt.Errorf("ambiguous selector %s", name)
+		}
+		return nil, errors.New("field not found")
+	}
+
+	chain := make([]*types.Var, 0, len(index))
+	current := owner
+	for _, i := range index {
+		next := derefType(current).Underlying().(*types.Struct).Field(i)
+		chain = append(chain, next)
+		current = next.Type()
+	}
+	return chain, nil
+}
+
+func (t SrcStructType) typesPkg() *types.Package {
+	if named, ok := t.GoType.(*types.Named); ok {
+		return named.Obj().Pkg()
+	}
+	return nil
+}
+
+// LookupFlattenedField returns the nested source field whose path concatenated without dots equals the name,
+// e.g. "AddressCity" resolves to "Address.City". It returns an error listing the candidates if the name can be split
+// in more than one way.
+func (t SrcStructType) LookupFlattenedField(name string) (SrcFieldType, error) {
+	fields := make(map[string]types.Type, len(t.Fields))
+	for fieldName, field := range t.Fields {
+		fields[fieldName] = field.Type.GoType
+	}
+
+	paths := flattenedPaths(fields, name)
+	switch len(paths) {
+	case 0:
+		return SrcFieldType{}, fmt.Errorf("field not found, field: %s, struct: %s", name, t.Name)
+	case 1:
+		return t.LookupField(paths[0])
+	default:
+		sort.Strings(paths)
+		return SrcFieldType{}, fmt.Errorf("ambiguous flattened field, field: %s, struct: %s, candidates: %s", name, t.Name, strings.Join(paths, ", "))
+	}
+}
+
+// flattenedPaths returns all dotted paths to the fields whose names concatenated give the name.
+// Every path segment must end on a word boundary, i.e. the rest of the name must start with an upper case letter.
+func flattenedPaths(fields map[string]types.Type, name string) []string {
+	var paths []string
+	for fieldName, fieldType := range fields {
+		if fieldName == name {
+			paths = append(paths, fieldName)
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(name, fieldName)
+		if !ok || rest == "" || !unicode.IsUpper([]rune(rest)[0]) || fieldType == nil {
+			continue
+		}
+		structType, ok := derefType(fieldType).Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+
+		nested := make(map[string]types.Type, structType.NumFields())
+		for i := 0; i < structType.NumFields(); i++ {
+			nested[structType.Field(i).Name()] = structType.Field(i).Type()
+		}
+		for _, path := range flattenedPaths(nested, rest) {
+			paths = append(paths, fieldName+"."+path)
+		}
+	}
+	return paths
+}
+
+func derefType(goType types.Type) types.Type {
+	if ptr, ok := goType.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return goType
+}
+
+// fieldTypeOf converts the type-checked type to the FieldTypeType with the name qualified by typeName.
+func (t SrcStructType) fieldTypeOf(goType types.Type) FieldTypeType {
+	result := FieldTypeType{}
+	if ptr, ok := goType.(*types.Pointer); ok {
+		result.IsPointer = true
+		goType = ptr.Elem()
+	}
+	result.GoType = goType
+	result.Name = t.typeName(goType)
+	return result
+}
+
+// typeName returns the name of the type qualified for the package the conversions are generated into,
+// or for the source package if it is not known.
+func (t SrcStructType) typeName(goType types.Type) string {
+	pkgPath := t.dstImportPath
+	if pkgPath == "" {
+		pkgPath = t.ImportPath
+	}
+	return qualifiedTypeName(goType, pkgPath)
+}
+
+// qualifiedTypeName returns the name of the type qualified for the package of the given import path.
+func qualifiedTypeName(goType types.Type, pkgPath string) string {
+	return types.TypeString(goType, func(pkg *types.Package) string {
+		if pkg.Path() == pkgPath {
+			return ""
+		}
+		return pkg.Name()
+	})
 }
 
 type FieldType struct {
 	Name           string
 	Type           FieldTypeType
 	OverriddenName string
+	Tag            reflect.StructTag
 }
 
 type FieldTypeType struct {
 	Name      string
 	IsPointer bool
+	// GoType is the type-checked type of the field, without the pointer if IsPointer is set.
+	// It may be nil if the type information is not available.
+	GoType types.Type
 }
 
+// MappingKind is the way the field value is converted.
+type MappingKind string
+
+const (
+	// KindDirect assigns the value as is.
+	KindDirect MappingKind = "direct"
+	// KindDeref dereferences the pointer source field, the destination field is left zero if it is nil.
+	// The reverse conversion takes the address of the destination field.
+	KindDeref MappingKind = "deref"
+	// KindRef takes the address of the source field if it is not zero, otherwise the destination field is left nil.
+	// The reverse conversion dereferences the destination field.
+	KindRef MappingKind = "ref"
+	// KindGuarded reads the nested source field reached through the pointer parents, the destination field
+	// is left zero if any of them is nil.
+	KindGuarded MappingKind = "guarded"
+	// KindConverter applies the converter functions declared in the spec.
+	KindConverter MappingKind = "converter"
+	// KindConst sets the destination field to the constant declared in the spec.
+	KindConst MappingKind = "const"
+	// KindNested converts the nested struct with the generated conversions of its struct pair,
+	// the pointers with their pointer variants.
+	KindNested MappingKind = "nested"
+	// KindPresence reads the value of the presence-tracking destination field in the patch, the conversion
+	// to the destination struct leaves the field absent.
+	KindPresence MappingKind = "presence"
+	// KindCustom converts the value with the user transformer.
+	KindCustom MappingKind = "custom"
+)
+
+// FieldMapping is the mapping of the destination field to the source field.
 type FieldMapping struct {
 	SrcField SrcFieldType
 	DstField DstFieldType
+	// Conv is the custom conversion declared in the spec, the source field is empty for the constants.
+	Conv FieldConv
+	// Kind is the way the field value is converted.
+	Kind MappingKind
+	// ModToDTO and ModToStruct are the statements of the field placed before the return statement of the conversion
+	// to the destination and source struct respectively, they declare the values returned by ValueToDTO and
+	// ValueToStruct. The declarations of the nested structs listed in TemplateData.NestedToStruct are not included.
+	ModToDTO    string
+	ModToStruct string
+
+	// pkgPath is the import path of the package the conversions are generated into.
+	pkgPath string
+}
+
+// typeName returns the name of the type of the field side qualified for the package the conversions are generated
+// into, the parsed name is returned if the type information is not available.
+func (f FieldMapping) typeName(t FieldTypeType) string {
+	if t.GoType == nil {
+		return t.Name
+	}
+	return qualifiedTypeName(t.GoType, f.pkgPath)
+}
+
+// ValueToDTO returns the expression of the destination field value in the conversion to the destination struct.
+func (f FieldMapping) ValueToDTO() string {
+	if f.SrcField.OverriddenName != "" {
+		return f.SrcField.OverriddenName
+	}
+	return "src." + f.SrcField.Name
+}
+
+// ValueToStruct returns the expression of the source field value in the conversion to the source struct.
+func (f FieldMapping) ValueToStruct() string {
+	if f.DstField.OverriddenName != "" {
+		return f.DstField.OverriddenName
+	}
+	return "src." + f.DstField.Name
 }
 
-func CreateMapping(srcStruct SrcStructType, dstStruct DstStructType) ([]FieldMapping, error) {
+// IsOneWay reports whether the field is set only by the conversion to the destination struct.
+func (f FieldMapping) IsOneWay() bool {
+	return f.Conv.Const != "" || f.Conv.ToDst != "" && f.Conv.ToSrc == ""
+}
+
+func CreateMapping(srcStruct SrcStructType, dstStruct DstStructType, cfg *GenerationConfig) ([]FieldMapping, error) {
+	srcStruct.dstImportPath = dstStruct.ImportPath
+	srcTags, err := srcStruct.MorphTags()
+	if err != nil {
+		return nil, err
+	}
+	for dstName := range srcTags {
+		// the source struct may be converted to several destination structs, the tag applies only to those having the field
+		if !dstStruct.HasField(dstName) {
+			delete(srcTags, dstName)
+		}
+	}
+
+	specs := make(map[string]FieldSpec, len(cfg.FieldSpecs))
+	for _, spec := range cfg.FieldSpecs {
+		if !dstStruct.HasField(spec.Dst) {
+			return nil, fmt.Errorf("spec refers to unknown destination field, field: %s, struct: %s", spec.Dst, dstStruct.Name)
+		}
+		if _, ok := specs[spec.Dst]; ok {
+			return nil, fmt.Errorf("several specs refer to the same destination field, field: %s, struct: %s", spec.Dst, dstStruct.Name)
+		}
+		specs[spec.Dst] = spec
+	}
+
 	var fields []FieldMapping
 	for _, dstField := range dstStruct.Fields {
-		srcField := dstField.SrcField
-		srcFieldType, ok := srcStruct.Fields[srcField]
-		if !ok {
-			return nil, fmt.Errorf("field not found, field: %s, struct: %s", srcField, srcStruct.Name)
+		spec, ok := specs[dstField.Name]
+		switch {
+		case ok && spec.Ignore:
+			continue
+		case ok && spec.Conv.Const != "":
+			fields = append(fields, FieldMapping{DstField: dstField, Conv: spec.Conv, pkgPath: dstStruct.ImportPath})
+			continue
+		case ok && spec.Src != "":
+			dstField.SrcField = spec.Src
+		default:
+			dstField, err = applySrcMorphTag(srcStruct, dstField, srcTags)
+			if err != nil {
+				return nil, err
+			}
 		}
-		if srcFieldType.Type.Name != dstField.Type.Name {
-			return nil, fmt.Errorf("field type mismatch, field: %s, src: %s, dst: %s", srcField, srcFieldType.Type.Name, dstField.Type.Name)
+
+		srcFieldType, err := lookupSrcField(srcStruct, dstField, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if spec.Conv.ToDst == "" && srcFieldType.Type.Name != dstField.Type.Name &&
+			matchTransformer(cfg.transformers(), srcFieldType, dstField) == nil {
+			return nil, fmt.Errorf("field type mismatch, field: %s, src: %s, dst: %s", srcFieldType.Name, srcFieldType.Type.Name, dstField.Type.Name)
 		}
 		fields = append(fields, FieldMapping{
 			SrcField: srcFieldType,
 			DstField: dstField,
+			Conv:     spec.Conv,
+			pkgPath:  dstStruct.ImportPath,
 		})
 	}
 
 	return fields, nil
 }
 
+// applySrcMorphTag binds the destination field to the source field that refers to it by the morphto tag
+// and checks that it agrees with the morph tag of the destination field. The srcTags are the morphto tags
+// naming the fields of the destination struct.
+func applySrcMorphTag(srcStruct SrcStructType, dstField DstFieldType, srcTags map[string]string) (DstFieldType, error) {
+	if srcName, ok := srcTags[dstField.Name]; ok {
+		if dstField.SrcField != dstField.Name && dstField.SrcField != srcName {
+			return dstField, fmt.Errorf("morph and morphto tags conflict, field: %s, morph tag: %s, morphto tag on: %s", dstField.Name, dstField.SrcField, srcName)
+		}
+		dstField.SrcField = srcName
+		return dstField, nil
+	}
+
+	if srcField, ok := srcStruct.Fields[dstField.SrcField]; ok {
+		if dstName, ok := srcField.Tag.Lookup(srcMorphTag); ok && dstName != dstField.Name && srcTags[dstName] == srcField.Name {
+			return dstField, fmt.Errorf("morph and morphto tags conflict, field: %s, src field: %s is bound by morphto tag to: %s", dstField.Name, srcField.Name, dstName)
+		}
+	}
+	return dstField, nil
+}
+
+// lookupSrcField returns the source field bound to the destination field. The fields that are not bound explicitly
+// by the morph tag and not found by name are resolved by the configured matchers and then by flattening.
+func lookupSrcField(srcStruct SrcStructType, dstField DstFieldType, cfg *GenerationConfig) (SrcFieldType, error) {
+	srcField, err := srcStruct.LookupField(dstField.SrcField)
+	if err == nil || dstField.SrcField != dstField.Name {
+		return srcField, err
+	}
+
+	switch names := matchSrcField(srcStruct, dstField, cfg.Matchers); len(names) {
+	case 0:
+	case 1:
+		return srcStruct.LookupField(names[0])
+	default:
+		return SrcFieldType{}, fmt.Errorf("ambiguous field match, field: %s, struct: %s, candidates: %s", dstField.Name, srcStruct.Name, strings.Join(names, ", "))
+	}
+
+	if cfg.Flatten {
+		return srcStruct.LookupFlattenedField(dstField.Name)
+	}
+	return srcField, err
+}
+
 var tmplDeref = template.Must(template.New("deref").Parse(`
-var {{.OverriddenName}} {{.Type.Name}}
-if src.{{.Name}} != nil {
+var {{.OverriddenName}} {{.TypeName}}
+if {{.Cond}} {
 	{{.OverriddenName}} = *src.{{.Name}}
 }
 `))
 
 var tmplRef = template.Must(template.New("ref").Parse(`
-var {{.OverriddenName}} *{{.Type.Name}}
-if src.{{.Name}} != *new({{.Type.Name}}) {
-	{{.OverriddenName}} = &src.{{.Name}}
+var {{.OverriddenName}} *{{.TypeName}}
+{{with .Cond}}if {{.}} {
+	{{$.OverriddenName}} = &src.{{$.Name}}
+}{{else}}{{.OverriddenName}} = &src.{{.Name}}{{end}}
+`))
+
+var tmplGuard = template.Must(template.New("guard").Parse(`
+var {{.OverriddenName}} {{if .Type.IsPointer}}*{{end}}{{.TypeName}}
+if {{.Cond}} {
+	{{.OverriddenName}} = src.{{.Name}}
 }
 `))
 
-func CreateMods(t *TemplateData) error {
+type modData struct {
+	FieldType
+	// TypeName is the name of the field type qualified for the destination package, without the pointer.
+	TypeName string
+	// Cond is the condition under which the value is taken, if empty, the value is taken always.
+	Cond string
+}
+
+var tmplNestedDecl = template.Must(template.New("nestedDecl").Parse(`
+var {{.OverriddenName}} {{if .Type.IsPointer}}*{{end}}{{.Type.Name}}
+`))
+
+var tmplNestedSet = template.Must(template.New("nestedSet").Parse(`
+{{with .Cond}}if {{.}} {
+{{end}}{{range .Allocs}}if {{.Target}} == nil {
+	{{.Target}} = new({{.Type}})
+}
+{{end}}{{.Target}} = {{.Value}}
+{{if .Cond}}}
+{{end}}`))
+
+type nestedSetData struct {
+	// Cond is the condition under which the value is set, it is used only when the parents have to be allocated.
+	Cond   string
+	Allocs []nestedAlloc
+	Target string
+	Value  string
+}
+
+type nestedAlloc struct {
+	Target string
+	Type   string
+}
+
+// CreateMods converts the values of the fields with the custom conversions declared in the spec and with the first
+// matching transformer, the built-in transformers are tried after the given ones.
+func CreateMods(t *TemplateData, transformers ...FieldTransformer) error {
+	transformers = append(transformers[:len(transformers):len(transformers)], builtinTransformers...)
 	for i, field := range t.Fields {
-		if isFromPtrToValue(field.SrcField, field.DstField) {
-			t.Fields[i].SrcField.OverriddenName = fmt.Sprintf("__synthetic__%s", field.SrcField.Name)
-			deref, err := renderDeref(t.Fields[i].SrcField.FieldType)
-			if err != nil {
-				return fmt.Errorf("error rendering deref: %w", err)
+		t.Fields[i].Kind = KindDirect
+		switch {
+		case field.Conv.Const != "":
+			t.Fields[i].Kind = KindConst
+			t.Fields[i].SrcField.OverriddenName = field.Conv.Const
+
+		case field.Conv.ToDst != "":
+			if len(nilGuard(field.SrcField)) > 0 {
+				return fmt.Errorf("converter of the field reached through a pointer is not supported, field: %s", field.SrcField.Name)
+			}
+			t.Fields[i].Kind = KindConverter
+			t.Fields[i].SrcField.OverriddenName = fmt.Sprintf("%s(src.%s)", field.Conv.ToDst, field.SrcField.Name)
+			if field.Conv.ToSrc != "" {
+				t.Fields[i].DstField.OverriddenName = fmt.Sprintf("%s(src.%s)", field.Conv.ToSrc, field.DstField.Name)
 			}
-			t.ModsToDTO = append(t.ModsToDTO, deref)
 
-			t.Fields[i].DstField.OverriddenName = fmt.Sprintf("__synthetic__%s", field.DstField.Name)
-			ref, err := renderRef(t.Fields[i].DstField.FieldType)
+		default:
+			transformer := matchTransformer(transformers, field.SrcField, field.DstField)
+			if transformer == nil {
+				continue
+			}
+			transform, err := transformer.TransformField(field)
 			if err != nil {
-				return fmt.Errorf("error rendering ref: %w", err)
+				return fmt.Errorf("error transforming field %s: %w", field.DstField.Name, err)
 			}
-			t.ModsToStruct = append(t.ModsToStruct, ref)
+			t.applyTransform(i, transform)
+		}
+	}
 
+	if t.cloner != nil {
+		err := t.cloner.cloneFields(t)
+		if err != nil {
+			return fmt.Errorf("error creating deep copy: %w", err)
 		}
-		if isFromValueToPtr(field.SrcField, field.DstField) {
-			t.Fields[i].SrcField.OverriddenName = fmt.Sprintf("__synthetic__%s", field.SrcField.Name)
-			ref, err := renderRef(t.Fields[i].SrcField.FieldType)
+	}
+	return createNestedMods(t)
+}
+
+// applyTransform sets the values and the mods of the field from its transform.
+func (t *TemplateData) applyTransform(i int, transform FieldTransform) {
+	field := &t.Fields[i]
+	field.Kind = transform.Kind
+	if field.Kind == "" {
+		field.Kind = KindCustom
+	}
+
+	field.SrcField.OverriddenName = transform.ToDTO.Value
+	field.ModToDTO = transform.ToDTO.Mod
+	if transform.ToDTO.Mod != "" {
+		t.ModsToDTO = append(t.ModsToDTO, transform.ToDTO.Mod)
+	}
+
+	field.DstField.OverriddenName = transform.ToStruct.Value
+	field.ModToStruct = transform.ToStruct.Mod
+	if transform.ToStruct.Mod != "" {
+		t.ModsToStruct = append(t.ModsToStruct, transform.ToStruct.Mod)
+	}
+}
+
+// createNestedMods rebuilds the nested structs of the source struct from the fields mapped by dotted paths.
+// Pointer parents are allocated only when the value of the field is present.
+func createNestedMods(t *TemplateData) error {
+	direct := make(map[string]bool, len(t.Fields))
+	for _, field := range t.Fields {
+		if len(field.SrcField.Parents) == 0 && !field.IsOneWay() {
+			direct[field.SrcField.Name] = true
+		}
+	}
+
+	declared := make(map[string]bool)
+	for i, field := range t.Fields {
+		if len(field.SrcField.Parents) == 0 || field.IsOneWay() {
+			continue
+		}
+
+		root := field.SrcField.Parents[0]
+		if direct[root.Name] {
+			return fmt.Errorf("field is mapped both directly and by a nested path, field: %s, path: %s", root.Name, field.SrcField.Name)
+		}
+		root.OverriddenName = SyntheticName(root.Name)
+		if !declared[root.Name] {
+			declared[root.Name] = true
+			decl, err := renderTemplate(tmplNestedDecl, root)
 			if err != nil {
-				return fmt.Errorf("error rendering ref: %w", err)
+				return fmt.Errorf("error rendering nested struct declaration: %w", err)
 			}
-			t.ModsToDTO = append(t.ModsToDTO, ref)
+			t.ModsToStruct = append(t.ModsToStruct, decl)
+			t.NestedToStruct = append(t.NestedToStruct, root)
+		}
 
-			t.Fields[i].DstField.OverriddenName = fmt.Sprintf("__synthetic__%s", field.DstField.Name)
-			deref, err := renderDeref(t.Fields[i].DstField.FieldType)
-			if err != nil {
-				return fmt.Errorf("error rendering deref: %w", err)
+		data := nestedSetData{
+			Target: root.OverriddenName + strings.TrimPrefix(field.SrcField.Name, root.Name),
+			Value:  "src." + field.DstField.Name,
+		}
+		if field.DstField.OverriddenName != "" {
+			data.Value = field.DstField.OverriddenName
+		}
+		for _, parent := range field.SrcField.Parents {
+			if parent.Type.IsPointer {
+				data.Allocs = append(data.Allocs, nestedAlloc{
+					Target: root.OverriddenName + strings.TrimPrefix(parent.Name, root.Name),
+					Type:   parent.Type.Name,
+				})
+			}
+		}
+		var decl string
+		if len(data.Allocs) > 0 {
+			if !field.DstField.Type.IsPointer && !isPlainExpr(data.Value) {
+				// the value, e.g. the deep copy, is evaluated once for both the condition and the assignment
+				decl = fmt.Sprintf("\n%s := %s\n", SyntheticName(field.DstField.Name), data.Value)
+				data.Value = SyntheticName(field.DstField.Name)
+			}
+			switch {
+			case field.DstField.Type.IsPointer:
+				data.Cond = fmt.Sprintf("src.%s != nil", field.DstField.Name)
+			case field.SrcField.Type.IsPointer:
+				data.Cond = data.Value + " != nil"
+			default:
+				data.Cond = notEmptyCond(data.Value, field.SrcField.Type.GoType, field.typeName(field.SrcField.Type))
 			}
-			t.ModsToStruct = append(t.ModsToStruct, deref)
 		}
+
+		set, err := renderTemplate(tmplNestedSet, data)
+		if err != nil {
+			return fmt.Errorf("error rendering nested struct field: %w", err)
+		}
+		set = decl + set
+		t.Fields[i].ModToStruct += set
+		t.ModsToStruct = append(t.ModsToStruct, set)
 	}
 
 	return nil
 }
 
-func renderDeref(field FieldType) (string, error) {
-	buff := &bytes.Buffer{}
-	err := tmplDeref.Execute(buff, field)
+// isPlainExpr reports whether the expression is a variable or a field selector, which is cheap to evaluate twice.
+func isPlainExpr(expr string) bool {
+	parsed, err := parser.ParseExpr(expr)
 	if err != nil {
-		return "", fmt.Errorf("error executing template: %w", err)
+		return false
+	}
+	for {
+		switch e := parsed.(type) {
+		case *ast.Ident:
+			return true
+		case *ast.SelectorExpr:
+			parsed = e.X
+		default:
+			return false
+		}
 	}
+}
 
-	return buff.String(), nil
+// NilGuard returns the condition that the pointer parents of the field are not nil,
+// it is empty if the field is not reached through pointers.
+func (f SrcFieldType) NilGuard() string {
+	return joinConds(nilGuard(f)...)
+}
+
+// nilGuard returns the conditions that protect access to the field through the pointer parents.
+func nilGuard(field SrcFieldType) []string {
+	var conds []string
+	for _, parent := range field.Parents {
+		if parent.Type.IsPointer {
+			conds = append(conds, fmt.Sprintf("src.%s != nil", parent.Name))
+		}
+	}
+	return conds
+}
+
+// SyntheticName returns the name of the variable declared in the generated code for the field,
+// e.g. __synthetic__Address_City for Address.City.
+func SyntheticName(name string) string {
+	return fmt.Sprintf("__synthetic__%s", strings.ReplaceAll(name, ".", "_"))
 }
 
-func renderRef(field FieldType) (string, error) {
+func renderDeref(field FieldType, typeName string, guard []string) (string, error) {
+	return renderTemplate(tmplDeref, modData{
+		FieldType: field,
+		TypeName:  typeName,
+		Cond:      joinConds(append(guard, fmt.Sprintf("src.%s != nil", field.Name))...),
+	})
+}
+
+func renderRef(field FieldType, typeName string, guard []string) (string, error) {
+	return renderTemplate(tmplRef, modData{
+		FieldType: field,
+		TypeName:  typeName,
+		Cond:      joinConds(append(guard, notEmptyCond("src."+field.Name, field.Type.GoType, typeName))...),
+	})
+}
+
+func renderGuard(field FieldType, typeName string, guard []string) (string, error) {
+	return renderTemplate(tmplGuard, modData{
+		FieldType: field,
+		TypeName:  typeName,
+		Cond:      joinConds(guard...),
+	})
+}
+
+func renderTemplate(tmpl *template.Template, data any) (string, error) {
 	buff := &bytes.Buffer{}
-	err := tmplRef.Execute(buff, field)
+	err := tmpl.Execute(buff, data)
 	if err != nil {
 		return "", fmt.Errorf("error executing template: %w", err)
 	}
@@ -288,69 +1127,335 @@ func renderRef(field FieldType) (string, error) {
 	return buff.String(), nil
 }
 
-func isFromPtrToValue(srcField SrcFieldType, dstField DstFieldType) bool {
-	return srcField.Type.IsPointer && !dstField.Type.IsPointer
+func joinConds(conds ...string) string {
+	nonEmpty := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		if cond != "" {
+			nonEmpty = append(nonEmpty, cond)
+		}
+	}
+	return strings.Join(nonEmpty, " && ")
 }
 
-func isFromValueToPtr(srcField SrcFieldType, dstField DstFieldType) bool {
-	return !srcField.Type.IsPointer && dstField.Type.IsPointer
-}
+// notEmptyCond returns the condition that checks whether the expression of the given type holds a non-zero value,
+// typeName is the name of the type qualified for the destination package. The `!= *new(T)` comparison is used only
+// for comparable types, since it does not compile for slices, maps, functions and structs that contain them.
+// For non-comparable structs and arrays an empty string is returned, which means that the value is always treated
+// as present.
+func notEmptyCond(expr string, goType types.Type, typeName string) string {
+	if goType == nil {
+		// no type information, assume that the type is comparable
+		return fmt.Sprintf("%s != *new(%s)", expr, typeName)
+	}
 
-func FormatAndWrite(buff *bytes.Buffer, fileName string) error {
-	// Run goimports on the generated file
-	formattedSource, err := imports.Process(fileName, buff.Bytes(), nil)
-	if err != nil {
-		slog.Error("Error running goimports on generated file", "error", err)
-		// Write the unformatted source to the file anyway, so the user can see what went wrong
-		formattedSource = buff.Bytes()
+	switch goType.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return fmt.Sprintf("len(%s) != 0", expr)
+	case *types.Signature:
+		return fmt.Sprintf("%s != nil", expr)
 	}
 
-	// Write the formatted source back to the file
-	err = os.WriteFile(fileName, formattedSource, 0644)
-	if err != nil {
-		return fmt.Errorf("error writing formatted source to file: %w", err)
+	if types.Comparable(goType) {
+		return fmt.Sprintf("%s != *new(%s)", expr, typeName)
 	}
+	return ""
+}
 
-	return nil
+func isFromPtrToValue(srcField SrcFieldType, dstField DstFieldType) bool {
+	return srcField.Type.IsPointer && !dstField.Type.IsPointer
+}
+
+func isFromValueToPtr(srcField SrcFieldType, dstField DstFieldType) bool {
+	return !srcField.Type.IsPointer && dstField.Type.IsPointer
 }
 
+// TemplateData is the data the output template is executed with. The fields and the methods of the types it refers to
+// are kept stable for the custom templates set by WithTemplate, the built-in template is an example of using them.
 type TemplateData struct {
+	// Header is the generated code marker and the recorded command line, it must start the generated file.
+	Header string
+	// Command is the recorded command line the file is generated with.
+	Command string
+	// FuncNameToDTO and FuncNameToStruct are the names of the conversion functions to the destination
+	// and source struct respectively, e.g. ConvertToPersonDTO and ConvertToPerson.
 	FuncNameToDTO    string
 	FuncNameToStruct string
+	// MethodToDTO and MethodToStruct report whether the conversions are methods of the converted struct,
+	// the receiver is named src as the parameter of the free function.
+	MethodToDTO    bool
+	MethodToStruct bool
+	// FuncNameToDTOPtr and FuncNameToStructPtr are the names of the pointer variants of the conversions,
+	// e.g. ConvertToPersonDTOPtr, they are empty unless the variants are enabled.
+	FuncNameToDTOPtr    string
+	FuncNameToStructPtr string
+	// FuncNamePatch is the name of the function applying the destination struct as a patch to the source struct,
+	// e.g. ApplyPersonPatchDTO, or of the method of the destination struct if MethodToStruct is set, e.g. ApplyToPerson.
+	// It is empty unless the patch is enabled.
+	FuncNamePatch string
+	// PatchMods are the statements of the patch function assigning the fields present in the patch.
+	PatchMods []string
+	// Collections are the slice and map variants of the conversions to the destination and source struct,
+	// they are empty unless the variants are enabled.
+	Collections []CollectionFunc
+	// SrcPkgPathImport is the import path of the source package, it is empty if the structs share the package.
 	SrcPkgPathImport string
-	SrcStructName    string
-	DistFilePkgName  string
-	DstStructName    string
-	ModsToDTO        []string
-	ModsToStruct     []string
-	Fields           []FieldMapping
+	// SrcStructName is the source struct name qualified for the destination package, e.g. domain.Person.
+	SrcStructName string
+	// DistFilePkgName is the name of the destination package the file is generated into.
+	DistFilePkgName string
+	DstStructName   string
+	// ModsToDTO and ModsToStruct are the statements placed before the return statement of the conversion
+	// to the destination and source struct respectively, they are the per-field mods of all the fields
+	// together with the declarations of the nested structs.
+	ModsToDTO    []string
+	ModsToStruct []string
+	// Fields are the mappings of the destination fields in their declaration order.
+	Fields []FieldMapping
+	// NestedToStruct are the top-level fields of the source struct rebuilt from the fields mapped by dotted paths.
+	NestedToStruct []FieldType
+	// Guard are the fields of the exhaustiveness guard, it is empty unless the guard is enabled.
+	Guard []GuardField
+	// Imports are the import paths of the packages referenced by the conversions declared in the spec.
+	Imports []string
+	// Converter is the interface of the conversions, it is nil unless the converter is enabled.
+	Converter *Converter
+	// CopyFuncs are the helpers deep-copying the values of the fields, one per copied type, they are empty
+	// unless some field is deep-copied.
+	CopyFuncs []CopyFunc
+
+	// cloner deep-copies the values of the fields, it is nil if the data is not created by CreateTemplateData.
+	cloner *cloner
 }
 
-var tmpl = template.Must(template.New("morph").Parse(`// Code generated by structmorph; DO NOT EDIT.
+var tmpl = template.Must(template.New("morph").Parse(`{{.Header}}
 
 package {{.DistFilePkgName}}
 
 {{if .SrcPkgPathImport}}import "{{.SrcPkgPathImport}}"{{end}}
-
-func {{.FuncNameToDTO}}(src {{.SrcStructName}}) {{.DstStructName}} {
+{{range .Imports}}import "{{.}}"
+{{end}}
+func {{if .MethodToDTO}}(src {{.SrcStructName}}) {{.FuncNameToDTO}}(){{else}}{{.FuncNameToDTO}}(src {{.SrcStructName}}){{end}} {{.DstStructName}} {
 	{{range .ModsToDTO -}}{{.}}{{end}}
 	return {{.DstStructName}}{
-		{{range .Fields}}{{.DstField.Name}}: {{with .SrcField.OverriddenName}}{{.}}{{else}}src.{{.SrcField.Name}}{{end}},
+		{{range .Fields}}{{.DstField.Name}}: {{.ValueToDTO}},
 		{{end}}
 	}
 }
 
-func {{.FuncNameToStruct}}(src {{.DstStructName}}) {{.SrcStructName}} {
+func {{if .MethodToStruct}}(src {{.DstStructName}}) {{.FuncNameToStruct}}(){{else}}{{.FuncNameToStruct}}(src {{.DstStructName}}){{end}} {{.SrcStructName}} {
 	{{range .ModsToStruct -}}{{.}}{{end}}
 	return {{.SrcStructName}}{
-		{{range .Fields}}{{.SrcField.Name}}: {{with .DstField.OverriddenName}}{{.}}{{else}}src.{{.DstField.Name}}{{end}},
+		{{range .Fields}}{{if not (or .SrcField.Parents .IsOneWay)}}{{.SrcField.Name}}: {{.ValueToStruct}},
+		{{end}}{{end}}{{range .NestedToStruct}}{{.Name}}: {{.OverriddenName}},
 		{{end}}
 	}
 }
-`))
+{{if .FuncNameToDTOPtr}}
+// {{.FuncNameToDTOPtr}} converts the pointer to {{.SrcStructName}}, nil is converted to nil.
+func {{if .MethodToDTO}}(src *{{.SrcStructName}}) {{.FuncNameToDTOPtr}}(){{else}}{{.FuncNameToDTOPtr}}(src *{{.SrcStructName}}){{end}} *{{.DstStructName}} {
+	if src == nil {
+		return nil
+	}
+	dst := {{if .MethodToDTO}}src.{{.FuncNameToDTO}}(){{else}}{{.FuncNameToDTO}}(*src){{end}}
+	return &dst
+}
+{{end}}{{if .FuncNameToStructPtr}}
+// {{.FuncNameToStructPtr}} converts the pointer to {{.DstStructName}}, nil is converted to nil.
+func {{if .MethodToStruct}}(src *{{.DstStructName}}) {{.FuncNameToStructPtr}}(){{else}}{{.FuncNameToStructPtr}}(src *{{.DstStructName}}){{end}} *{{.SrcStructName}} {
+	if src == nil {
+		return nil
+	}
+	dst := {{if .MethodToStruct}}src.{{.FuncNameToStruct}}(){{else}}{{.FuncNameToStruct}}(*src){{end}}
+	return &dst
+}
+{{end}}{{if .FuncNamePatch}}
+// {{.FuncNamePatch}} applies the fields of {{.DstStructName}} present in the patch to {{.SrcStructName}},
+// the other fields are left untouched.
+func {{if .MethodToStruct}}(src {{.DstStructName}}) {{.FuncNamePatch}}(dst *{{.SrcStructName}}){{else}}{{.FuncNamePatch}}(dst *{{.SrcStructName}}, src {{.DstStructName}}){{end}} {
+	{{range .PatchMods -}}{{.}}{{end -}}
+}
+{{end}}{{range .Collections}}
+// {{.Slice}} converts the slice of {{.Src}}, nil is converted to nil.
+func {{.Slice}}(src []{{.Src}}) []{{.Dst}} {
+	if src == nil {
+		return nil
+	}
+	dst := make([]{{.Dst}}, len(src))
+	for i, v := range src {
+		dst[i] = {{.Call}}
+	}
+	return dst
+}
+{{if .PtrSlice}}
+// {{.PtrSlice}} converts the slice of the pointers to {{.Src}}, nil is converted to nil.
+func {{.PtrSlice}}(src []*{{.Src}}) []*{{.Dst}} {
+	if src == nil {
+		return nil
+	}
+	dst := make([]*{{.Dst}}, len(src))
+	for i, v := range src {
+		dst[i] = {{.PtrCall}}
+	}
+	return dst
+}
+{{end}}
+// {{.Map}} converts the map of {{.Src}}, nil is converted to nil.
+func {{.Map}}[K comparable](src map[K]{{.Src}}) map[K]{{.Dst}} {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[K]{{.Dst}}, len(src))
+	for k, v := range src {
+		dst[k] = {{.Call}}
+	}
+	return dst
+}
+{{end}}{{with .Converter}}
+// {{.Name}} converts between {{$.SrcStructName}} and {{$.DstStructName}}, accept it instead of calling
+// the conversions to mock them in tests.
+{{- if .Group}}
+//
+//structmorph:group {{.Group}}
+{{- end}}
+type {{.Name}} interface {
+	{{.ToDTO}}(src {{$.SrcStructName}}) {{$.DstStructName}}
+	{{.ToStruct}}(src {{$.DstStructName}}) {{$.SrcStructName}}
+}
+
+// {{.Impl}} implements {{.Name}} with the generated conversions.
+type {{.Impl}} struct{}
+
+func ({{.Impl}}) {{.ToDTO}}(src {{$.SrcStructName}}) {{$.DstStructName}} {
+	return {{if $.MethodToDTO}}src.{{$.FuncNameToDTO}}(){{else}}{{$.FuncNameToDTO}}(src){{end}}
+}
+
+func ({{.Impl}}) {{.ToStruct}}(src {{$.DstStructName}}) {{$.SrcStructName}} {
+	return {{if $.MethodToStruct}}src.{{$.FuncNameToStruct}}(){{else}}{{$.FuncNameToStruct}}(src){{end}}
+}
+{{end}}{{range .CopyFuncs}}
+// {{.Name}} deep-copies {{.Type}}.
+func {{.Name}}(v {{.Type}}) {{.Type}} {
+	{{.Body}}
+}
+{{end}}{{if .Guard}}
+// The conversion fails to compile when the fields of {{.SrcStructName}} change, regenerate the converters to fix it.
+var _ = struct { {{- range .Guard}}
+	{{.Decl}} // {{.Comment}}{{end}}
+}({{.SrcStructName}}{})
+{{end}}`))
+
+// setMethodNames names the conversions as methods of the structs they convert. The generated file belongs to the destination
+// package, so the source struct of another package can't get a method and the constructor of the destination is used.
+func setMethodNames(data *TemplateData, srcStruct SrcStructType, dstStruct DstStructType) {
+	data.MethodToStruct = true
+	data.FuncNameToStruct = "To" + srcStruct.Name
+	if srcStruct.ImportPath == dstStruct.ImportPath {
+		data.MethodToDTO = true
+		data.FuncNameToDTO = "To" + dstStruct.Name
+		return
+	}
+	data.FuncNameToDTO = dstStruct.Name + "From" + srcStruct.Name
+	slog.Info("Methods can't be declared on a struct of another package, generating a constructor instead",
+		"struct", srcStruct.qualifiedName(), "constructor", data.FuncNameToDTO)
+}
+
+// freeFuncNames returns the names of the conversions that are not methods.
+func (data TemplateData) freeFuncNames() []string {
+	var names []string
+	if !data.MethodToDTO {
+		names = append(names, data.FuncNameToDTO)
+		if data.FuncNameToDTOPtr != "" {
+			names = append(names, data.FuncNameToDTOPtr)
+		}
+	}
+	if !data.MethodToStruct {
+		names = append(names, data.FuncNameToStruct)
+		if data.FuncNameToStructPtr != "" {
+			names = append(names, data.FuncNameToStructPtr)
+		}
+	}
+	if data.FuncNamePatch != "" && !data.MethodToStruct {
+		names = append(names, data.FuncNamePatch)
+	}
+	for _, collection := range data.Collections {
+		names = append(names, collection.Slice, collection.Map)
+		if collection.PtrSlice != "" {
+			names = append(names, collection.PtrSlice)
+		}
+	}
+	for _, copyFunc := range data.CopyFuncs {
+		names = append(names, copyFunc.Name)
+	}
+	return names
+}
+
+// CollectionFunc is the conversion in one direction applied to the elements of the slices and maps.
+type CollectionFunc struct {
+	// Slice, PtrSlice and Map are the names of the variants, e.g. ConvertToPersonDTOs, ConvertToPersonDTOPtrs
+	// and ConvertToPersonDTOMap, PtrSlice is empty unless the pointer variants are enabled.
+	Slice    string
+	PtrSlice string
+	Map      string
+	// Src and Dst are the element types.
+	Src string
+	Dst string
+	// Call and PtrCall are the conversions of the element v and of the pointer element v respectively.
+	Call    string
+	PtrCall string
+}
+
+// collectionFuncs returns the collection variants of the conversions to the destination and source struct.
+func (data TemplateData) collectionFuncs() []CollectionFunc {
+	toDTO := CollectionFunc{
+		Slice: plural(data.FuncNameToDTO),
+		Map:   data.FuncNameToDTO + "Map",
+		Src:   data.SrcStructName,
+		Dst:   data.DstStructName,
+		Call:  conversionCall(data.MethodToDTO, data.FuncNameToDTO, "v"),
+	}
+	toStruct := CollectionFunc{
+		Slice: plural(data.FuncNameToStruct),
+		Map:   data.FuncNameToStruct + "Map",
+		Src:   data.DstStructName,
+		Dst:   data.SrcStructName,
+		Call:  conversionCall(data.MethodToStruct, data.FuncNameToStruct, "v"),
+	}
+	if data.FuncNameToDTOPtr != "" {
+		toDTO.PtrSlice = data.FuncNameToDTOPtr + "s"
+		toDTO.PtrCall = conversionCall(data.MethodToDTO, data.FuncNameToDTOPtr, "v")
+		toStruct.PtrSlice = data.FuncNameToStructPtr + "s"
+		toStruct.PtrCall = conversionCall(data.MethodToStruct, data.FuncNameToStructPtr, "v")
+	}
+	return []CollectionFunc{toDTO, toStruct}
+}
+
+// conversionCall returns the expression converting the argument with the conversion function or method.
+func conversionCall(method bool, name, arg string) string {
+	if method {
+		return fmt.Sprintf("%s.%s()", arg, name)
+	}
+	return fmt.Sprintf("%s(%s)", name, arg)
+}
+
+// plural returns the plural of the name ending with a noun, e.g. ConvertToAddresses.
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiouAEIOU", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
 
 func (data TemplateData) GenerateCode(output io.Writer) error {
-	err := tmpl.Execute(output, data)
+	return data.Execute(tmpl, output)
+}
+
+// Execute executes the output template with the data.
+func (data TemplateData) Execute(t *template.Template, output io.Writer) error {
+	err := t.Execute(output, data)
 	if err != nil {
 		return fmt.Errorf("error executing template: %w", err)
 	}
@@ -1,9 +1,15 @@
 package structmorph
 
 import (
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseStructName(t *testing.T) {
@@ -38,6 +44,26 @@ func TestParseStructName(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "ParseStructName with import path",
+			args: args{
+				rawName: "github.com/user/project/mypackage.MyStruct",
+			},
+			want: StructName{
+				Package: "mypackage",
+				Name:    "MyStruct",
+				Path:    "github.com/user/project/mypackage",
+			},
+			wantErr: false,
+		},
+		{
+			name: "ParseStructName with import path without struct name",
+			args: args{
+				rawName: "github.com/user/mypackage",
+			},
+			want:    StructName{},
+			wantErr: true,
+		},
 		// for now, it's not possible to test this case,
 		// because we cannot distinguish between a package name and a struct name in that stage
 		//{
@@ -74,3 +100,324 @@ func TestParseStructName(t *testing.T) {
 		})
 	}
 }
+
+func TestSrcStructType_LookupFlattenedField(t *testing.T) {
+	str := types.Typ[types.String]
+	owner := types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, nil, "NameFirst", str, false),
+	}, nil)
+	ownerName := types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, nil, "First", str, false),
+	}, nil)
+	address := types.NewPointer(types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, nil, "City", str, false),
+	}, nil))
+
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "main", Name: "Person"},
+		Fields: map[string]SrcFieldType{
+			"Owner":     {FieldType: FieldType{Name: "Owner", Type: FieldTypeType{Name: "Owner", GoType: owner}}},
+			"OwnerName": {FieldType: FieldType{Name: "OwnerName", Type: FieldTypeType{Name: "OwnerName", GoType: ownerName}}},
+			"Address":   {FieldType: FieldType{Name: "Address", Type: FieldTypeType{Name: "Address", IsPointer: true, GoType: address.Elem()}}},
+			"Addr":      {FieldType: FieldType{Name: "Addr", Type: FieldTypeType{Name: "string", GoType: str}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		field    string
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name:     "LookupFlattenedField through pointer",
+			field:    "AddressCity",
+			wantPath: "Address.City",
+		},
+		{
+			name:    "LookupFlattenedField with ambiguous split",
+			field:   "OwnerNameFirst",
+			wantErr: "candidates: Owner.NameFirst, OwnerName.First",
+		},
+		{
+			name:    "LookupFlattenedField without word boundary",
+			field:   "AddressCityName",
+			wantErr: "field not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srcStruct.LookupFlattenedField(tt.field)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.Name)
+		})
+	}
+}
+
+func TestSrcStructType_LookupField__promoted(t *testing.T) {
+	pkg := types.NewPackage("example.com/person", "person")
+	str := types.Typ[types.String]
+	newNamed := func(name string, fields ...*types.Var) *types.Named {
+		return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(fields, nil), nil)
+	}
+	left := newNamed("Left", types.NewField(token.NoPos, pkg, "Tag", str, false))
+	right := newNamed("Right", types.NewField(token.NoPos, pkg, "Tag", str, false))
+	audit := newNamed("Audit", types.NewField(token.NoPos, pkg, "UpdatedBy", str, false))
+	person := newNamed("Person",
+		types.NewField(token.NoPos, pkg, "Left", left, true),
+		types.NewField(token.NoPos, pkg, "Right", right, true),
+		types.NewField(token.NoPos, pkg, "Audit", types.NewPointer(audit), true),
+	)
+
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "person", Name: "Person"},
+		ImportPath: "example.com/person",
+		GoType:     person,
+		Fields: map[string]SrcFieldType{
+			"Left":  {FieldType: FieldType{Name: "Left", Type: FieldTypeType{Name: "Left", GoType: left}}},
+			"Right": {FieldType: FieldType{Name: "Right", Type: FieldTypeType{Name: "Right", GoType: right}}},
+			"Audit": {FieldType: FieldType{Name: "Audit", Type: FieldTypeType{Name: "Audit", IsPointer: true, GoType: audit}}},
+		},
+	}
+
+	got, err := srcStruct.LookupField("UpdatedBy")
+	assert.NoError(t, err)
+	assert.Equal(t, "Audit.UpdatedBy", got.Name)
+	assert.Equal(t, []FieldType{srcStruct.Fields["Audit"].FieldType}, got.Parents)
+
+	_, err = srcStruct.LookupField("Tag")
+	assert.ErrorContains(t, err, "ambiguous selector Tag")
+
+	got, err = srcStruct.LookupField("Left.Tag")
+	assert.NoError(t, err)
+	assert.Equal(t, "Left.Tag", got.Name)
+}
+
+func TestCreateMapping__morphTags(t *testing.T) {
+	newSrc := func(name string, tag reflect.StructTag) SrcFieldType {
+		return SrcFieldType{FieldType: FieldType{Name: name, Type: FieldTypeType{Name: "int"}, Tag: tag}}
+	}
+	newDst := func(name, srcField string) DstFieldType {
+		return DstFieldType{FieldType: FieldType{Name: name, Type: FieldTypeType{Name: "int"}}, SrcField: srcField}
+	}
+
+	tests := []struct {
+		name      string
+		srcFields []SrcFieldType
+		dstFields []DstFieldType
+		wantSrc   []string
+		wantErr   string
+	}{
+		{
+			name:      "CreateMapping with tag on the source field",
+			srcFields: []SrcFieldType{newSrc("EmployeesCount", `morphto:"TeamSize"`)},
+			dstFields: []DstFieldType{newDst("TeamSize", "TeamSize")},
+			wantSrc:   []string{"EmployeesCount"},
+		},
+		{
+			name:      "CreateMapping with agreeing tags on both sides",
+			srcFields: []SrcFieldType{newSrc("EmployeesCount", `morphto:"TeamSize"`)},
+			dstFields: []DstFieldType{newDst("TeamSize", "EmployeesCount")},
+			wantSrc:   []string{"EmployeesCount"},
+		},
+		{
+			name:      "CreateMapping with disagreeing tags",
+			srcFields: []SrcFieldType{newSrc("EmployeesCount", `morphto:"TeamSize"`), newSrc("Size", "")},
+			dstFields: []DstFieldType{newDst("TeamSize", "Size")},
+			wantErr:   "morph and morphto tags conflict",
+		},
+		{
+			name:      "CreateMapping with source field bound to another destination field",
+			srcFields: []SrcFieldType{newSrc("Size", `morphto:"TeamSize"`)},
+			dstFields: []DstFieldType{newDst("TeamSize", "TeamSize"), newDst("Size", "Size")},
+			wantErr:   "morph and morphto tags conflict",
+		},
+		{
+			name:      "CreateMapping with several source fields referring to the same destination field",
+			srcFields: []SrcFieldType{newSrc("Size", `morphto:"TeamSize"`), newSrc("Count", `morphto:"TeamSize"`)},
+			dstFields: []DstFieldType{newDst("TeamSize", "TeamSize")},
+			wantErr:   "several source fields refer to the same destination field",
+		},
+		{
+			name:      "CreateMapping with morph tag of the source struct that is the destination of another pair",
+			srcFields: []SrcFieldType{newSrc("TeamSize", `morph:"EmployeesCount"`)},
+			dstFields: []DstFieldType{newDst("TeamSize", "TeamSize")},
+			wantSrc:   []string{"TeamSize"},
+		},
+		{
+			name:      "CreateMapping with tag referring to field of another destination struct",
+			srcFields: []SrcFieldType{newSrc("Size", `morphto:"TeamSize"`)},
+			dstFields: []DstFieldType{newDst("Size", "Size")},
+			wantSrc:   []string{"Size"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcStruct := SrcStructType{StructName: StructName{Package: "main", Name: "Organization"}, Fields: map[string]SrcFieldType{}}
+			for _, field := range tt.srcFields {
+				srcStruct.Fields[field.Name] = field
+			}
+			dstStruct := DstStructType{StructName: StructName{Package: "main", Name: "OrganizationDTO"}, Fields: tt.dstFields}
+
+			got, err := CreateMapping(srcStruct, dstStruct, DefaultGenerationConfig())
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var gotSrc []string
+			for _, field := range got {
+				gotSrc = append(gotSrc, field.SrcField.Name)
+			}
+			assert.Equal(t, tt.wantSrc, gotSrc)
+		})
+	}
+}
+
+func TestCreateMapping__fieldSpecs(t *testing.T) {
+	newSrc := func(name, typeName string, tag reflect.StructTag) SrcFieldType {
+		return SrcFieldType{FieldType: FieldType{Name: name, Type: FieldTypeType{Name: typeName}, Tag: tag}}
+	}
+	newDst := func(name, typeName string) DstFieldType {
+		return DstFieldType{FieldType: FieldType{Name: name, Type: FieldTypeType{Name: typeName}}, SrcField: name}
+	}
+
+	tests := []struct {
+		name      string
+		srcFields []SrcFieldType
+		dstFields []DstFieldType
+		specs     []FieldSpec
+		wantSrc   []string
+		wantErr   string
+	}{
+		{
+			name:      "CreateMapping with spec overriding the morph tag",
+			srcFields: []SrcFieldType{newSrc("Size", "int", `morphto:"TeamSize"`), newSrc("Count", "int", "")},
+			dstFields: []DstFieldType{newDst("TeamSize", "int")},
+			specs:     []FieldSpec{{Dst: "TeamSize", Src: "Count"}},
+			wantSrc:   []string{"Count"},
+		},
+		{
+			name:      "CreateMapping with ignored and constant fields",
+			srcFields: []SrcFieldType{newSrc("Name", "string", "")},
+			dstFields: []DstFieldType{newDst("Name", "string"), newDst("Password", "string"), newDst("Version", "int")},
+			specs:     []FieldSpec{{Dst: "Password", Ignore: true}, {Dst: "Version", Conv: FieldConv{Const: "2"}}},
+			wantSrc:   []string{"Name", ""},
+		},
+		{
+			name:      "CreateMapping with converter between different types",
+			srcFields: []SrcFieldType{newSrc("Age", "int", "")},
+			dstFields: []DstFieldType{newDst("Age", "string")},
+			specs:     []FieldSpec{{Dst: "Age", Src: "Age", Conv: FieldConv{ToDst: "strconv.Itoa"}}},
+			wantSrc:   []string{"Age"},
+		},
+		{
+			name:      "CreateMapping with spec referring to unknown destination field",
+			srcFields: []SrcFieldType{newSrc("Name", "string", "")},
+			dstFields: []DstFieldType{newDst("Name", "string")},
+			specs:     []FieldSpec{{Dst: "Unknown", Ignore: true}},
+			wantErr:   "spec refers to unknown destination field",
+		},
+		{
+			name:      "CreateMapping with several specs of the same destination field",
+			srcFields: []SrcFieldType{newSrc("Name", "string", "")},
+			dstFields: []DstFieldType{newDst("Name", "string")},
+			specs:     []FieldSpec{{Dst: "Name", Ignore: true}, {Dst: "Name", Src: "Name"}},
+			wantErr:   "several specs refer to the same destination field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcStruct := SrcStructType{StructName: StructName{Package: "main", Name: "Person"}, Fields: map[string]SrcFieldType{}}
+			for _, field := range tt.srcFields {
+				srcStruct.Fields[field.Name] = field
+			}
+			dstStruct := DstStructType{StructName: StructName{Package: "main", Name: "PersonDTO"}, Fields: tt.dstFields}
+
+			got, err := CreateMapping(srcStruct, dstStruct, newGenerationConfig(WithFieldSpecs(tt.specs...)))
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var gotSrc []string
+			for _, field := range got {
+				gotSrc = append(gotSrc, field.SrcField.Name)
+			}
+			assert.Equal(t, tt.wantSrc, gotSrc)
+		})
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{
+			name:    "IsGenerated with header",
+			content: GeneratedHeader + "\n\npackage main\n",
+			want:    true,
+		},
+		{
+			name:    "IsGenerated with header after build tags",
+			content: "//go:build linux\n\n" + GeneratedHeader + "\n\npackage main\n",
+			want:    true,
+		},
+		{
+			name:    "IsGenerated without header",
+			content: "package main\n\nfunc ConvertToPersonDTO() {}\n",
+			want:    false,
+		},
+		{
+			name:    "IsGenerated with header after package clause",
+			content: "package main\n\n" + GeneratedHeader + "\n",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsGenerated([]byte(tt.content)))
+		})
+	}
+}
+
+func TestCheckOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	generated := filepath.Join(dir, "morph_generated.go")
+	handWritten := filepath.Join(dir, "morph_handwritten.go")
+	require.NoError(t, os.WriteFile(generated, []byte(GeneratedHeader+"\n\npackage main\n"), 0644))
+	require.NoError(t, os.WriteFile(handWritten, []byte("package main\n"), 0644))
+
+	assert.NoError(t, checkOverwrite(generated))
+	assert.NoError(t, checkOverwrite(filepath.Join(dir, "morph_missing.go")))
+	assert.ErrorContains(t, checkOverwrite(handWritten), "refusing to overwrite")
+}
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "ConvertToPerson", want: "ConvertToPersons"},
+		{name: "ConvertToAddress", want: "ConvertToAddresses"},
+		{name: "ConvertToBox", want: "ConvertToBoxes"},
+		{name: "ConvertToBatch", want: "ConvertToBatches"},
+		{name: "ConvertToCompany", want: "ConvertToCompanies"},
+		{name: "ConvertToKey", want: "ConvertToKeys"},
+		{name: "ConvertToPersonDTO", want: "ConvertToPersonDTOs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plural(tt.name))
+		})
+	}
+}
@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=allsupportedtypes.TypeDTO --src=allsupportedtypes.Type
+//structmorph:checksum sha256:8633c43d97c22887e826bec9db0aa46347a25ec80a97404761fe61dacfe11b2e
 
 package allsupportedtypes
 
@@ -0,0 +1,6 @@
+package annotated
+
+type Address struct {
+	City   string
+	Street string
+}
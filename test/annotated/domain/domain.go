@@ -0,0 +1,11 @@
+package domain
+
+type Order struct {
+	ID    int
+	Total *float64
+}
+
+type User struct {
+	Name  string
+	Email string
+}
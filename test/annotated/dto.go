@@ -0,0 +1,26 @@
+package annotated
+
+// the source package of the annotations is imported for the structmorph:from directives
+import _ "structmorph/test/annotated/domain"
+
+//go:generate go run ../../cmd/structmorph/structmorph.go ./...
+
+//structmorph:from domain.Order
+type OrderDTO struct {
+	ID    int
+	Total float64
+}
+
+type (
+	//structmorph:from domain.User --guard
+	UserDTO struct {
+		Name  string
+		Email string
+	}
+
+	//structmorph:from Address --match=ignore-case
+	AddressDTO struct {
+		CITY   string
+		Street string
+	}
+)
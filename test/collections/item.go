@@ -0,0 +1,12 @@
+package collections
+
+type Item struct {
+	SKU      string
+	Quantity int
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=collections.Item --dst=collections.ItemDTO --ptr --collections
+type ItemDTO struct {
+	SKU      string
+	Quantity int
+}
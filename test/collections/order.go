@@ -0,0 +1,16 @@
+package collections
+
+type Order struct {
+	ID         string
+	Items      []Item
+	Gifts      []*Item
+	ItemsBySKU map[string]Item
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=collections.Order --dst=collections.OrderDTO --collections --nested
+type OrderDTO struct {
+	ID         string
+	Items      []ItemDTO
+	Gifts      []*ItemDTO
+	ItemsBySKU map[string]ItemDTO
+}
@@ -0,0 +1,10 @@
+package converter
+
+type Address struct {
+	City string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=converter.Address --dst=converter.AddressDTO --converter
+type AddressDTO struct {
+	City string
+}
@@ -0,0 +1,12 @@
+package converter
+
+type Order struct {
+	ID    string
+	Total int64
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=converter.Order --dst=converter.OrderDTO --converter-group=Mapper --methods
+type OrderDTO struct {
+	ID    string
+	Total int64
+}
@@ -0,0 +1,12 @@
+package converter
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=converter.Person --dst=converter.PersonDTO --converter-group=Mapper
+type PersonDTO struct {
+	Name string
+	Age  int
+}
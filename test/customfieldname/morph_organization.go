@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=customfieldname.OrganizationDTO --src=customfieldname.Organization
+//structmorph:checksum sha256:165cc3663eb0e652108d8562021d5f4655d9b981f3e16501362c2ce766c839e0
 
 package customfieldname
 
@@ -0,0 +1,52 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=customtemplate.PersonDTO --src=customtemplate.Person --template=morph.tmpl,struct.tmpl
+//structmorph:checksum sha256:14142433dc2138572b26078acca6b6ea1a87bd397fdbb6065b200446f4ec08e8
+
+package customtemplate
+
+// ConvertToPersonDTO converts Person to PersonDTO.
+//
+// Fields:
+//   - Name: direct
+//   - Age: deref
+//   - Email: ref
+func ConvertToPersonDTO(src Person) (dst PersonDTO) {
+
+	dst.Name = src.Name
+
+	var __synthetic__Age int
+	if src.Age != nil {
+		__synthetic__Age = *src.Age
+	}
+
+	dst.Age = __synthetic__Age
+
+	var __synthetic__Email *string
+	if src.Email != *new(string) {
+		__synthetic__Email = &src.Email
+	}
+
+	dst.Email = __synthetic__Email
+	return dst
+}
+
+// ConvertToPerson converts PersonDTO to Person.
+func ConvertToPerson(src PersonDTO) (dst Person) {
+
+	dst.Name = src.Name
+
+	var __synthetic__Age *int
+	if src.Age != *new(int) {
+		__synthetic__Age = &src.Age
+	}
+
+	dst.Age = __synthetic__Age
+
+	var __synthetic__Email string
+	if src.Email != nil {
+		__synthetic__Email = *src.Email
+	}
+
+	dst.Email = __synthetic__Email
+	return dst
+}
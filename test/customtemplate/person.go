@@ -0,0 +1,14 @@
+package customtemplate
+
+type Person struct {
+	Name  string
+	Age   *int
+	Email string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=customtemplate.Person --dst=customtemplate.PersonDTO --template=morph.tmpl,struct.tmpl
+type PersonDTO struct {
+	Name  string
+	Age   int
+	Email *string
+}
@@ -0,0 +1,38 @@
+package deepcopy
+
+type Person struct {
+	Name     string
+	Tags     []string
+	Labels   map[string]string
+	Manager  *Person
+	Address  *Address
+	Previous []Address
+	Scores   map[string][]int
+	Shared   []string
+}
+
+type Address struct {
+	City  string
+	Lines []string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=deepcopy.Person --dst=deepcopy.PersonDTO --deep-copy
+type PersonDTO struct {
+	Name     string
+	Tags     []string
+	Labels   map[string]string
+	Manager  *Person
+	Address  *Address
+	Previous []Address
+	Scores   map[string][]int
+	Shared   []string `morphcopy:"shallow"`
+}
+
+type Contact struct {
+	Address *Address
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=deepcopy.Contact --dst=deepcopy.ContactDTO --deep-copy
+type ContactDTO struct {
+	Lines []string `morph:"Address.Lines"`
+}
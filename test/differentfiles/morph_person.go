@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=differentfiles.PersonDTO --src=differentfiles.Person
+//structmorph:checksum sha256:220abcf6ae87333940ecd27d040820720c9d90dbbdc86af05694490ad208684e
 
 package differentfiles
 
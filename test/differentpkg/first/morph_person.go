@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=first.PersonDTO --root=../. --src=second.Person
+//structmorph:checksum sha256:b6ce8634f7e4636fcd6a224e719ebec868deffaaef3d1be8076a28a993295c2a
 
 package first
 
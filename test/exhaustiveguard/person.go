@@ -0,0 +1,25 @@
+package exhaustiveguard
+
+type Person struct {
+	Name    string
+	Sex     string
+	Age     int
+	Address Address
+	Audit
+}
+
+type Address struct {
+	City string
+}
+
+type Audit struct {
+	UpdatedBy string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=exhaustiveguard.Person --dst=exhaustiveguard.PersonDTO --guard
+type PersonDTO struct {
+	Name      string
+	Age       int
+	City      string `morph:"Address.City"`
+	UpdatedBy string
+}
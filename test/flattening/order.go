@@ -0,0 +1,34 @@
+package flattening
+
+type Order struct {
+	ID       int
+	Address  Address
+	Owner    *Owner
+	Customer Customer
+}
+
+type Address struct {
+	City string
+	Zip  string
+}
+
+type Owner struct {
+	Name string
+}
+
+type Customer struct {
+	Contact Contact
+}
+
+type Contact struct {
+	Email string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=flattening.Order --dst=flattening.OrderDTO --flatten
+type OrderDTO struct {
+	ID                   int
+	AddressCity          string
+	AddressZip           string
+	OwnerName            string
+	CustomerContactEmail string
+}
@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=main.PersonDTO --src=main.Person
+//structmorph:checksum sha256:b9b75dbdec42cbd47caa556f156b0cd9edbe385f083bd77fdf55a005f1708ea6
 
 package main
 
@@ -0,0 +1,10 @@
+// Package handwritten has converters written by hand in the file with the name structmorph would generate.
+package handwritten
+
+func ConvertToPersonDTO(src Person) PersonDTO {
+	return PersonDTO{Name: src.Name, Age: src.Age}
+}
+
+func ConvertToPerson(src PersonDTO) Person {
+	return Person{Name: src.Name, Age: src.Age}
+}
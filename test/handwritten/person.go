@@ -0,0 +1,11 @@
+package handwritten
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+type PersonDTO struct {
+	Name string
+	Age  int
+}
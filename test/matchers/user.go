@@ -0,0 +1,16 @@
+package matchers
+
+type User struct {
+	UserID    int
+	Username  string
+	Email     string `json:"email_address"`
+	FirstName string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=matchers.User --dst=matchers.UserDTO --match=initialism,ignore-case,tag:json --trim-prefix=Dto
+type UserDTO struct {
+	UserId       int
+	UserName     string
+	Mail         string `json:"email_address"`
+	DtoFirstName string
+}
@@ -0,0 +1,7 @@
+package api
+
+//go:generate go run ../../../cmd/structmorph/structmorph.go --src=methods.Person --dst=api.PersonResponse --root=../. --methods
+type PersonResponse struct {
+	Name string
+	Age  int
+}
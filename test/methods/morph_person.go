@@ -0,0 +1,42 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=methods.PersonDTO --methods --src=methods.Person
+//structmorph:checksum sha256:13ef15418dcddf50f50592c0911d192d89afbcb72fa245f8bc344ceab4921dd0
+
+package methods
+
+func (src Person) ToPersonDTO() PersonDTO {
+
+	var __synthetic__Address_City *string
+	if src.Address != nil && src.Address.City != *new(string) {
+		__synthetic__Address_City = &src.Address.City
+	}
+
+	return PersonDTO{
+		Name: src.Name,
+		Age:  src.Age,
+		City: __synthetic__Address_City,
+	}
+}
+
+func (src PersonDTO) ToPerson() Person {
+
+	var __synthetic__City string
+	if src.City != nil {
+		__synthetic__City = *src.City
+	}
+
+	var __synthetic__Address *Address
+
+	if src.City != nil {
+		if __synthetic__Address == nil {
+			__synthetic__Address = new(Address)
+		}
+		__synthetic__Address.City = __synthetic__City
+	}
+
+	return Person{
+		Name:    src.Name,
+		Age:     src.Age,
+		Address: __synthetic__Address,
+	}
+}
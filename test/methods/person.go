@@ -0,0 +1,18 @@
+package methods
+
+type Person struct {
+	Name    string
+	Age     int
+	Address *Address
+}
+
+type Address struct {
+	City string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=methods.Person --dst=methods.PersonDTO --methods
+type PersonDTO struct {
+	Name string
+	Age  int
+	City *string `morph:"Address.City"`
+}
@@ -0,0 +1,56 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=api.PersonDTO --root=../. --src=nestedfields.Person
+//structmorph:checksum sha256:2de890f61e2ba2aa7bcbbeb7d3804ee79b461ec2babcd5fc1213b1e015e886fd
+
+package api
+
+import "structmorph/test/nestedfields"
+
+func ConvertToPersonDTO(src nestedfields.Person) PersonDTO {
+
+	var __synthetic__Company_Title string
+	if src.Company != nil {
+		__synthetic__Company_Title = src.Company.Title
+	}
+
+	var __synthetic__Company_Location *nestedfields.Location
+	if src.Company != nil {
+		__synthetic__Company_Location = src.Company.Location
+	}
+
+	return PersonDTO{
+		Name:         src.Name,
+		City:         src.Address.City,
+		CompanyTitle: __synthetic__Company_Title,
+		Location:     __synthetic__Company_Location,
+	}
+}
+
+func ConvertToPerson(src PersonDTO) nestedfields.Person {
+
+	var __synthetic__Address nestedfields.Address
+
+	__synthetic__Address.City = src.City
+
+	var __synthetic__Company *nestedfields.Company
+
+	if src.CompanyTitle != *new(string) {
+		if __synthetic__Company == nil {
+			__synthetic__Company = new(nestedfields.Company)
+		}
+		__synthetic__Company.Title = src.CompanyTitle
+	}
+
+	if src.Location != nil {
+		if __synthetic__Company == nil {
+			__synthetic__Company = new(nestedfields.Company)
+		}
+		__synthetic__Company.Location = src.Location
+	}
+
+	return nestedfields.Person{
+		Name:    src.Name,
+		Address: __synthetic__Address,
+		Company: __synthetic__Company,
+	}
+}
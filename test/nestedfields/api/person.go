@@ -0,0 +1,12 @@
+// Package api reads the nested fields of the struct from another package through the pointer parents.
+package api
+
+import "structmorph/test/nestedfields"
+
+//go:generate go run ../../../cmd/structmorph/structmorph.go --src=nestedfields.Person --dst=api.PersonDTO --root=../.
+type PersonDTO struct {
+	Name         string
+	City         string                 `morph:"Address.City"`
+	CompanyTitle string                 `morph:"Company.Title"`
+	Location     *nestedfields.Location `morph:"Company.Location"`
+}
@@ -0,0 +1,75 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=nestedfields.PersonDTO --src=nestedfields.Person
+//structmorph:checksum sha256:3b8395e189c9e20593ed2cd646b6e30970915bda9ae8f9d2f72ce421e8657d19
+
+package nestedfields
+
+func ConvertToPersonDTO(src Person) PersonDTO {
+
+	var __synthetic__Address_Street string
+	if src.Address.Street != nil {
+		__synthetic__Address_Street = *src.Address.Street
+	}
+
+	var __synthetic__Company_Title string
+	if src.Company != nil {
+		__synthetic__Company_Title = src.Company.Title
+	}
+
+	var __synthetic__Company_Location_Country *string
+	if src.Company != nil && src.Company.Location != nil && src.Company.Location.Country != *new(string) {
+		__synthetic__Company_Location_Country = &src.Company.Location.Country
+	}
+
+	return PersonDTO{
+		Name:           src.Name,
+		City:           src.Address.City,
+		Street:         __synthetic__Address_Street,
+		CompanyTitle:   __synthetic__Company_Title,
+		CompanyCountry: __synthetic__Company_Location_Country,
+	}
+}
+
+func ConvertToPerson(src PersonDTO) Person {
+
+	var __synthetic__Street *string
+	if src.Street != *new(string) {
+		__synthetic__Street = &src.Street
+	}
+
+	var __synthetic__CompanyCountry string
+	if src.CompanyCountry != nil {
+		__synthetic__CompanyCountry = *src.CompanyCountry
+	}
+
+	var __synthetic__Address Address
+
+	__synthetic__Address.City = src.City
+
+	__synthetic__Address.Street = __synthetic__Street
+
+	var __synthetic__Company *Company
+
+	if src.CompanyTitle != *new(string) {
+		if __synthetic__Company == nil {
+			__synthetic__Company = new(Company)
+		}
+		__synthetic__Company.Title = src.CompanyTitle
+	}
+
+	if src.CompanyCountry != nil {
+		if __synthetic__Company == nil {
+			__synthetic__Company = new(Company)
+		}
+		if __synthetic__Company.Location == nil {
+			__synthetic__Company.Location = new(Location)
+		}
+		__synthetic__Company.Location.Country = __synthetic__CompanyCountry
+	}
+
+	return Person{
+		Name:    src.Name,
+		Address: __synthetic__Address,
+		Company: __synthetic__Company,
+	}
+}
@@ -0,0 +1,30 @@
+package nestedfields
+
+type Person struct {
+	Name    string
+	Address Address
+	Company *Company
+}
+
+type Address struct {
+	City   string
+	Street *string
+}
+
+type Company struct {
+	Title    string
+	Location *Location
+}
+
+type Location struct {
+	Country string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=nestedfields.Person --dst=nestedfields.PersonDTO
+type PersonDTO struct {
+	Name           string
+	City           string  `morph:"Address.City"`
+	Street         string  `morph:"Address.Street"`
+	CompanyTitle   string  `morph:"Company.Title"`
+	CompanyCountry *string `morph:"Company.Location.Country"`
+}
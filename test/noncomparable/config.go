@@ -0,0 +1,24 @@
+package noncomparable
+
+type Config struct {
+	Tags     []string
+	Labels   map[string]string
+	Settings Settings
+	Callback func() string
+	Window   [2]int
+	Shards   [2][]string
+}
+
+type Settings struct {
+	Hosts []string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=noncomparable.Config --dst=noncomparable.ConfigDTO
+type ConfigDTO struct {
+	Tags     *[]string
+	Labels   *map[string]string
+	Settings *Settings
+	Callback *func() string
+	Window   *[2]int
+	Shards   *[2][]string
+}
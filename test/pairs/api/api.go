@@ -0,0 +1,11 @@
+package api
+
+type PersonDTO struct {
+	Name        string
+	AddressCity string
+}
+
+type AddressDTO struct {
+	City   string
+	Street string
+}
@@ -0,0 +1,37 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command go run structmorph/test/pairs/gen --dst=structmorph/test/pairs/api.PersonDTO --flatten --src=structmorph/test/pairs/domain.Person
+//structmorph:checksum sha256:aa12073b3d58c1f2b35ed8fe684d15575742c8690d861a0180f49e3eb23aa332
+
+package api
+
+import "structmorph/test/pairs/domain"
+
+func ConvertToPersonDTO(src domain.Person) PersonDTO {
+
+	var __synthetic__Address_City string
+	if src.Address != nil {
+		__synthetic__Address_City = src.Address.City
+	}
+
+	return PersonDTO{
+		Name:        src.Name,
+		AddressCity: __synthetic__Address_City,
+	}
+}
+
+func ConvertToPerson(src PersonDTO) domain.Person {
+
+	var __synthetic__Address *domain.Address
+
+	if src.AddressCity != *new(string) {
+		if __synthetic__Address == nil {
+			__synthetic__Address = new(domain.Address)
+		}
+		__synthetic__Address.City = src.AddressCity
+	}
+
+	return domain.Person{
+		Name:    src.Name,
+		Address: __synthetic__Address,
+	}
+}
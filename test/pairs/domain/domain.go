@@ -0,0 +1,11 @@
+package domain
+
+type Person struct {
+	Name    string
+	Address *Address
+}
+
+type Address struct {
+	City   string
+	Street string
+}
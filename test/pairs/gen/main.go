@@ -0,0 +1,18 @@
+package main
+
+import (
+	"log"
+	"structmorph"
+	"structmorph/test/pairs/api"
+	"structmorph/test/pairs/domain"
+)
+
+func main() {
+	err := structmorph.GeneratePairs(".",
+		structmorph.Pair[domain.Person, api.PersonDTO](structmorph.WithFlattening()),
+		structmorph.Pair[domain.Address, api.AddressDTO](),
+	)
+	if err != nil {
+		log.Fatalf("Error generating code: %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package pairs declares the struct pairs in the generator program instead of the go:generate lines.
+package pairs
+
+//go:generate go run ./gen
@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=partialfields.PersonDTO --src=partialfields.Person
+//structmorph:checksum sha256:c008ba9956c534540dcafa068f50c45893a6e9affaaa004c48a1f39f053db0a3
 
 package partialfields
 
@@ -0,0 +1,114 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=patch.PersonPatchDTO --patch --src=patch.Person
+//structmorph:checksum sha256:ba37b52570109bb379dea6a025ab64f49367155df4d12a011e1c725781d9a33d
+
+package patch
+
+func ConvertToPersonPatchDTO(src Person) PersonPatchDTO {
+
+	var __synthetic__Name *string
+	if src.Name != *new(string) {
+		__synthetic__Name = &src.Name
+	}
+
+	var __synthetic__Address_City *string
+	if src.Address != nil && src.Address.City != *new(string) {
+		__synthetic__Address_City = &src.Address.City
+	}
+
+	var __synthetic__Address_Lines []string
+	if src.Address != nil {
+		__synthetic__Address_Lines = src.Address.Lines
+	}
+
+	return PersonPatchDTO{
+		ID:     src.ID,
+		Name:   __synthetic__Name,
+		Email:  src.Email,
+		Age:    *new(Optional[int]),
+		City:   __synthetic__Address_City,
+		Lines:  __synthetic__Address_Lines,
+		Tags:   src.Tags,
+		Labels: src.Labels,
+	}
+}
+
+func ConvertToPerson(src PersonPatchDTO) Person {
+
+	var __synthetic__Name string
+	if src.Name != nil {
+		__synthetic__Name = *src.Name
+	}
+
+	__synthetic__Age, _ := src.Age.Get()
+
+	var __synthetic__City string
+	if src.City != nil {
+		__synthetic__City = *src.City
+	}
+
+	var __synthetic__Address *Address
+
+	if src.City != nil {
+		if __synthetic__Address == nil {
+			__synthetic__Address = new(Address)
+		}
+		__synthetic__Address.City = __synthetic__City
+	}
+
+	if len(src.Lines) != 0 {
+		if __synthetic__Address == nil {
+			__synthetic__Address = new(Address)
+		}
+		__synthetic__Address.Lines = src.Lines
+	}
+
+	return Person{
+		ID:      src.ID,
+		Name:    __synthetic__Name,
+		Email:   src.Email,
+		Age:     __synthetic__Age,
+		Tags:    src.Tags,
+		Labels:  src.Labels,
+		Address: __synthetic__Address,
+	}
+}
+
+// ApplyPersonPatchDTO applies the fields of PersonPatchDTO present in the patch to Person,
+// the other fields are left untouched.
+func ApplyPersonPatchDTO(dst *Person, src PersonPatchDTO) {
+
+	if src.Name != nil {
+		dst.Name = *src.Name
+	}
+
+	if src.Email != nil {
+		dst.Email = src.Email
+	}
+
+	if v, ok := src.Age.Get(); ok {
+		dst.Age = v
+	}
+
+	if src.City != nil {
+		if dst.Address == nil {
+			dst.Address = new(Address)
+		}
+		dst.Address.City = *src.City
+	}
+
+	if src.Lines != nil {
+		if dst.Address == nil {
+			dst.Address = new(Address)
+		}
+		dst.Address.Lines = src.Lines
+	}
+
+	if src.Tags != nil {
+		dst.Tags = src.Tags
+	}
+
+	if src.Labels != nil {
+		dst.Labels = src.Labels
+	}
+}
@@ -0,0 +1,43 @@
+package patch
+
+type Person struct {
+	ID       string
+	Name     string
+	Email    *string
+	Age      int
+	Nickname string
+	Address  *Address
+	Tags     []string
+	Labels   map[string]string
+}
+
+type Address struct {
+	City  string
+	Lines []string
+}
+
+// Optional is the presence-tracking value of the patch.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=patch.Person --dst=patch.PersonPatchDTO --patch
+type PersonPatchDTO struct {
+	ID     string
+	Name   *string
+	Email  *string
+	Age    Optional[int]
+	City   *string  `morph:"Address.City"`
+	Lines  []string `morph:"Address.Lines"`
+	Tags   []string
+	Labels map[string]string
+}
@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=pointers.OrganizationDTO --src=pointers.Organization
+//structmorph:checksum sha256:b8a4b1425882ef76b8fd50d78397467131b32de3e12348d13e6ad26aa59b4fb3
 
 package pointers
 
@@ -0,0 +1,6 @@
+package base
+
+type Model struct {
+	ID        int
+	CreatedBy string
+}
@@ -0,0 +1,28 @@
+package promotedfields
+
+import "structmorph/test/promotedfields/base"
+
+type Person struct {
+	base.Model
+	*Audit
+	Named
+	Name string
+}
+
+type Audit struct {
+	UpdatedBy string
+}
+
+type Named struct {
+	Name     string
+	Nickname string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=promotedfields.Person --dst=promotedfields.PersonDTO
+type PersonDTO struct {
+	ID        int
+	CreatedBy string
+	UpdatedBy string
+	Name      string
+	Nickname  string
+}
@@ -0,0 +1,12 @@
+package ptrvariants
+
+type Address struct {
+	City   string
+	Street string
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=ptrvariants.Address --dst=ptrvariants.AddressDTO --ptr
+type AddressDTO struct {
+	City   string
+	Street string
+}
@@ -0,0 +1,16 @@
+package ptrvariants
+
+type Person struct {
+	Name    string
+	Home    *Address
+	Work    Address
+	Billing *Address
+}
+
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=ptrvariants.Person --dst=ptrvariants.PersonDTO --ptr --nested
+type PersonDTO struct {
+	Name    string
+	Home    *AddressDTO
+	Work    AddressDTO
+	Billing *AddressDTO
+}
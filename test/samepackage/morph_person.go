@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=samepackage.PersonDTO --src=samepackage.Person
+//structmorph:checksum sha256:e127344395928faa9538a11ac8d2cc591e6fef72aba04cf4aa183e7c58c3816b
 
 package samepackage
 
@@ -1,4 +1,6 @@
 // Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=main.PersonDTO --src=another.Person
+//structmorph:checksum sha256:804c9ab34fb3f8a980f2f21bcbed59cdeb5414f2c325324413122494019f1f36
 
 package main
 
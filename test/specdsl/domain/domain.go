@@ -0,0 +1,12 @@
+package domain
+
+type Person struct {
+	FIO      string
+	Age      int
+	Password string
+	Address  Address
+}
+
+type Address struct {
+	City string
+}
@@ -0,0 +1,36 @@
+package specdsl
+
+import (
+	"strconv"
+	"strings"
+
+	"structmorph/spec"
+	"structmorph/test/specdsl/domain"
+)
+
+//go:generate go run ../../cmd/structmorph/structmorph.go ./...
+
+const Version = 2
+
+type PersonDTO struct {
+	FullName string
+	Age      string
+	City     string
+	Password string
+	Version  int
+	Source   string
+}
+
+var _ = spec.New[domain.Person, PersonDTO](
+	spec.FromTo("FIO", "FullName"),
+	spec.FromTo("Age", "Age", spec.With(strconv.Itoa, parseAge)),
+	spec.FromTo("Address.City", "City", spec.With(strings.ToUpper, nil)),
+	spec.Ignore("Password"),
+	spec.Const("Version", Version),
+	spec.Const("Source", "domain"),
+)
+
+func parseAge(age string) int {
+	n, _ := strconv.Atoi(age)
+	return n
+}
@@ -0,0 +1,23 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=list.OrganizationListItem --root=../. --src=srcmorphtags.Organization
+//structmorph:checksum sha256:474160fb08dc3bbdca90906bd0992a0c6734803160d5ab747c184b2cf856ada9
+
+package list
+
+import "structmorph/test/srcmorphtags"
+
+func ConvertToOrganizationListItem(src srcmorphtags.Organization) OrganizationListItem {
+
+	return OrganizationListItem{
+		Title:          src.Title,
+		EmployeesCount: src.EmployeesCount,
+	}
+}
+
+func ConvertToOrganization(src OrganizationListItem) srcmorphtags.Organization {
+
+	return srcmorphtags.Organization{
+		Title:          src.Title,
+		EmployeesCount: src.EmployeesCount,
+	}
+}
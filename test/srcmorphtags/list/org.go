@@ -0,0 +1,10 @@
+package list
+
+// OrganizationListItem is converted from srcmorphtags.Organization too, the morphto tags naming the fields
+// it lacks are ignored.
+//
+//go:generate go run ../../../cmd/structmorph/structmorph.go --src=srcmorphtags.Organization --dst=list.OrganizationListItem --root=../.
+type OrganizationListItem struct {
+	Title          string
+	EmployeesCount int
+}
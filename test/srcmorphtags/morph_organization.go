@@ -0,0 +1,25 @@
+// Code generated by structmorph; DO NOT EDIT.
+//structmorph:command structmorph --dst=srcmorphtags.OrganizationDTO --src=srcmorphtags.Organization
+//structmorph:checksum sha256:5809420db083b512725b16a7c70749097c4cc685131edb8cf3214470655fa8f0
+
+package srcmorphtags
+
+func ConvertToOrganizationDTO(src Organization) OrganizationDTO {
+
+	return OrganizationDTO{
+		Title:       src.Title,
+		Description: src.Description,
+		TeamSize:    src.EmployeesCount,
+		FoundedYear: src.Founded,
+	}
+}
+
+func ConvertToOrganization(src OrganizationDTO) Organization {
+
+	return Organization{
+		Title:          src.Title,
+		Description:    src.Description,
+		EmployeesCount: src.TeamSize,
+		Founded:        src.FoundedYear,
+	}
+}
@@ -0,0 +1,28 @@
+package srcmorphtags
+
+type Organization struct {
+	Title          string
+	Description    string
+	EmployeesCount int `morphto:"TeamSize"`
+	Founded        int `morphto:"FoundedYear"`
+}
+
+// OrganizationDTO has almost no morph tags, as if it was generated from the API specification.
+//
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=srcmorphtags.Organization --dst=srcmorphtags.OrganizationDTO
+type OrganizationDTO struct {
+	Title       string
+	Description string
+	TeamSize    int
+	FoundedYear int `morph:"Founded"`
+}
+
+// OrganizationView is converted from OrganizationDTO, whose morph tag names the field of Organization
+// and is not applied to this pair.
+//
+//go:generate go run ../../cmd/structmorph/structmorph.go --src=srcmorphtags.OrganizationDTO --dst=srcmorphtags.OrganizationView --methods
+type OrganizationView struct {
+	Title    string
+	TeamSize int
+	Year     int `morph:"FoundedYear"`
+}
@@ -1,12 +1,38 @@
 package structmorph__test
 
 import (
+	"os"
+	"path/filepath"
 	"structmorph"
 	"structmorph/test/allsupportedtypes"
+	"structmorph/test/annotated"
+	"structmorph/test/annotated/domain"
+	"structmorph/test/collections"
+	"structmorph/test/converter"
 	"structmorph/test/customfieldname"
+	"structmorph/test/customtemplate"
+	"structmorph/test/deepcopy"
+	"structmorph/test/exhaustiveguard"
+	"structmorph/test/flattening"
+	"structmorph/test/matchers"
+	"structmorph/test/methods"
+	methodsapi "structmorph/test/methods/api"
+	"structmorph/test/nestedfields"
+	nestedfieldsapi "structmorph/test/nestedfields/api"
+	"structmorph/test/noncomparable"
+	pairsapi "structmorph/test/pairs/api"
+	pairsdomain "structmorph/test/pairs/domain"
 	"structmorph/test/partialfields"
+	"structmorph/test/patch"
 	"structmorph/test/pointers"
+	"structmorph/test/promotedfields"
+	"structmorph/test/ptrvariants"
+	"structmorph/test/specdsl"
+	specdomain "structmorph/test/specdsl/domain"
+	"structmorph/test/srcmorphtags"
+	srcmorphtagslist "structmorph/test/srcmorphtags/list"
 	"testing"
+	"unsafe"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/go-faker/faker/v4/pkg/options"
@@ -138,3 +164,696 @@ func TestGenerate__pointers__emptyToPointer(t *testing.T) {
 	assert.Equal(t, *org.Description, *convertedOrg.Description)
 	assert.Equal(t, org.Priority, convertedOrg.Priority)
 }
+
+func TestGenerate__noncomparable(t *testing.T) {
+	// Setup
+	cfg := noncomparable.Config{
+		Tags:     []string{"tag"},
+		Labels:   map[string]string{"key": "value"},
+		Settings: noncomparable.Settings{Hosts: []string{"localhost"}},
+		Callback: func() string { return "called" },
+		Window:   [2]int{1, 2},
+		Shards:   [2][]string{{"a"}, {"b"}},
+	}
+
+	// When
+	cfgDTO := noncomparable.ConvertToConfigDTO(cfg)
+	convertedCfg := noncomparable.ConvertToConfig(cfgDTO)
+
+	// Then
+	assert.Equal(t, cfg.Window, *cfgDTO.Window)
+	assert.Equal(t, cfg.Shards, *cfgDTO.Shards)
+	assert.Equal(t, cfg.Window, convertedCfg.Window)
+	assert.Equal(t, cfg.Shards, convertedCfg.Shards)
+	assert.Equal(t, cfg.Tags, *cfgDTO.Tags)
+	assert.Equal(t, cfg.Labels, *cfgDTO.Labels)
+	assert.Equal(t, cfg.Settings, *cfgDTO.Settings)
+	assert.Equal(t, "called", (*cfgDTO.Callback)())
+
+	assert.Equal(t, cfg.Tags, convertedCfg.Tags)
+	assert.Equal(t, cfg.Labels, convertedCfg.Labels)
+	assert.Equal(t, cfg.Settings, convertedCfg.Settings)
+	assert.Equal(t, "called", convertedCfg.Callback())
+}
+
+func TestGenerate__noncomparable__emptyToPointer(t *testing.T) {
+	// Setup
+	cfg := noncomparable.Config{
+		Tags:   []string{},
+		Labels: nil,
+	}
+
+	// When
+	cfgDTO := noncomparable.ConvertToConfigDTO(cfg)
+	convertedCfg := noncomparable.ConvertToConfig(cfgDTO)
+
+	// Then
+	assert.Nil(t, cfgDTO.Tags)
+	assert.Nil(t, cfgDTO.Labels)
+	assert.NotNil(t, cfgDTO.Settings)
+	assert.Nil(t, cfgDTO.Callback)
+	assert.Nil(t, cfgDTO.Window)
+	assert.NotNil(t, cfgDTO.Shards)
+
+	assert.Nil(t, convertedCfg.Tags)
+	assert.Nil(t, convertedCfg.Labels)
+	assert.Nil(t, convertedCfg.Callback)
+}
+
+func TestGenerate__nestedfields(t *testing.T) {
+	// Setup
+	person := nestedfields.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+
+	// When
+	personDTO := nestedfields.ConvertToPersonDTO(person)
+	convertedPerson := nestedfields.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, person.Address.City, personDTO.City)
+	assert.Equal(t, *person.Address.Street, personDTO.Street)
+	assert.Equal(t, person.Company.Title, personDTO.CompanyTitle)
+	assert.Equal(t, person.Company.Location.Country, *personDTO.CompanyCountry)
+
+	assert.Equal(t, person, convertedPerson)
+}
+
+func TestGenerate__nestedfields__nilParents(t *testing.T) {
+	// Setup
+	person := nestedfields.Person{
+		Name:    "Name",
+		Company: nil,
+	}
+
+	// When
+	personDTO := nestedfields.ConvertToPersonDTO(person)
+	convertedPerson := nestedfields.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Empty(t, personDTO.CompanyTitle)
+	assert.Nil(t, personDTO.CompanyCountry)
+
+	assert.Equal(t, person.Name, convertedPerson.Name)
+	assert.Nil(t, convertedPerson.Company)
+}
+
+func TestGenerate__nestedfields__allocateOnlyRequiredParents(t *testing.T) {
+	// Setup
+	personDTO := nestedfields.PersonDTO{
+		CompanyTitle: "Title",
+	}
+
+	// When
+	person := nestedfields.ConvertToPerson(personDTO)
+
+	// Then
+	require.NotNil(t, person.Company)
+	assert.Equal(t, "Title", person.Company.Title)
+	assert.Nil(t, person.Company.Location)
+}
+
+func TestGenerate__nestedfieldsOtherPackage(t *testing.T) {
+	// Setup
+	person := nestedfields.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+
+	// When
+	personDTO := nestedfieldsapi.ConvertToPersonDTO(person)
+	convertedPerson := nestedfieldsapi.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, person.Address.City, personDTO.City)
+	assert.Equal(t, person.Company.Title, personDTO.CompanyTitle)
+	assert.Same(t, person.Company.Location, personDTO.Location)
+
+	assert.Equal(t, person.Name, convertedPerson.Name)
+	assert.Equal(t, person.Address.City, convertedPerson.Address.City)
+	assert.Equal(t, person.Company, convertedPerson.Company)
+}
+
+func TestGenerate__nestedfieldsOtherPackage__nilParents(t *testing.T) {
+	// When
+	personDTO := nestedfieldsapi.ConvertToPersonDTO(nestedfields.Person{Name: "Name"})
+	convertedPerson := nestedfieldsapi.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Empty(t, personDTO.CompanyTitle)
+	assert.Nil(t, personDTO.Location)
+	assert.Nil(t, convertedPerson.Company)
+}
+
+func TestGenerate__flattening(t *testing.T) {
+	// Setup
+	order := flattening.Order{}
+	err := faker.FakeData(&order)
+	require.NoError(t, err)
+
+	// When
+	orderDTO := flattening.ConvertToOrderDTO(order)
+	convertedOrder := flattening.ConvertToOrder(orderDTO)
+
+	// Then
+	assert.Equal(t, order.Address.City, orderDTO.AddressCity)
+	assert.Equal(t, order.Address.Zip, orderDTO.AddressZip)
+	assert.Equal(t, order.Owner.Name, orderDTO.OwnerName)
+	assert.Equal(t, order.Customer.Contact.Email, orderDTO.CustomerContactEmail)
+
+	assert.Equal(t, order, convertedOrder)
+}
+
+func TestGenerate__promotedfields(t *testing.T) {
+	// Setup
+	person := promotedfields.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+	person.Named.Name = ""
+
+	// When
+	personDTO := promotedfields.ConvertToPersonDTO(person)
+	convertedPerson := promotedfields.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, person.ID, personDTO.ID)
+	assert.Equal(t, person.CreatedBy, personDTO.CreatedBy)
+	assert.Equal(t, person.UpdatedBy, personDTO.UpdatedBy)
+	assert.Equal(t, person.Name, personDTO.Name)
+	assert.Equal(t, person.Nickname, personDTO.Nickname)
+
+	assert.Equal(t, person, convertedPerson)
+}
+
+func TestGenerate__matchers(t *testing.T) {
+	// Setup
+	user := matchers.User{}
+	err := faker.FakeData(&user)
+	require.NoError(t, err)
+
+	// When
+	userDTO := matchers.ConvertToUserDTO(user)
+	convertedUser := matchers.ConvertToUser(userDTO)
+
+	// Then
+	assert.Equal(t, user.UserID, userDTO.UserId)
+	assert.Equal(t, user.Username, userDTO.UserName)
+	assert.Equal(t, user.Email, userDTO.Mail)
+	assert.Equal(t, user.FirstName, userDTO.DtoFirstName)
+
+	assert.Equal(t, user, convertedUser)
+}
+
+func TestGenerate__srcmorphtags(t *testing.T) {
+	// Setup
+	org := srcmorphtags.Organization{}
+	err := faker.FakeData(&org)
+	require.NoError(t, err)
+
+	// When
+	orgDTO := srcmorphtags.ConvertToOrganizationDTO(org)
+	convertedOrg := srcmorphtags.ConvertToOrganization(orgDTO)
+
+	// Then
+	assert.Equal(t, org.EmployeesCount, orgDTO.TeamSize)
+	assert.Equal(t, org.Founded, orgDTO.FoundedYear)
+
+	assert.Equal(t, org, convertedOrg)
+}
+
+func TestGenerate__srcmorphtagsChained(t *testing.T) {
+	// Setup
+	orgDTO := srcmorphtags.OrganizationDTO{}
+	err := faker.FakeData(&orgDTO)
+	require.NoError(t, err)
+
+	// When
+	orgView := orgDTO.ToOrganizationView()
+	convertedDTO := orgView.ToOrganizationDTO()
+
+	// Then
+	assert.Equal(t, orgDTO.TeamSize, orgView.TeamSize)
+	assert.Equal(t, orgDTO.FoundedYear, orgView.Year)
+	assert.Empty(t, convertedDTO.Description)
+	convertedDTO.Description = orgDTO.Description
+	assert.Equal(t, orgDTO, convertedDTO)
+}
+
+func TestGenerate__srcmorphtagsSeveralDestinations(t *testing.T) {
+	// Setup
+	org := srcmorphtags.Organization{}
+	err := faker.FakeData(&org)
+	require.NoError(t, err)
+
+	// When
+	orgDTO := srcmorphtags.ConvertToOrganizationDTO(org)
+	orgItem := srcmorphtagslist.ConvertToOrganizationListItem(org)
+
+	// Then
+	assert.Equal(t, org.EmployeesCount, orgDTO.TeamSize)
+	assert.Equal(t, org.Title, orgItem.Title)
+	assert.Equal(t, org.EmployeesCount, orgItem.EmployeesCount, "the morphto tag naming a field of another destination is ignored")
+}
+
+func TestGenerate__exhaustiveguard(t *testing.T) {
+	// Setup
+	person := exhaustiveguard.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+
+	// When
+	personDTO := exhaustiveguard.ConvertToPersonDTO(person)
+	convertedPerson := exhaustiveguard.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, person.Address.City, personDTO.City)
+	assert.Equal(t, person.UpdatedBy, personDTO.UpdatedBy)
+	assert.Empty(t, convertedPerson.Sex)
+}
+
+func TestCheck__upToDate(t *testing.T) {
+	diff, err := structmorph.Check("partialfields.Person", "partialfields.PersonDTO", structmorph.WithProjectRoot("partialfields"))
+
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestCheck__outdated(t *testing.T) {
+	// the file on disk is generated without the guard
+	diff, err := structmorph.Check("partialfields.Person", "partialfields.PersonDTO",
+		structmorph.WithProjectRoot("partialfields"), structmorph.WithExhaustivenessGuard())
+
+	require.NoError(t, err)
+	assert.Contains(t, diff, "partialfields/morph_person.go (generated)")
+	assert.Contains(t, diff, "+var _ = struct {")
+}
+
+func TestRender(t *testing.T) {
+	file, err := structmorph.Render("partialfields.Person", "partialfields.PersonDTO", structmorph.WithProjectRoot("partialfields"))
+	require.NoError(t, err)
+
+	current, err := os.ReadFile(file.Path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("partialfields", "morph_person.go"), filepath.Join(filepath.Base(filepath.Dir(file.Path)), filepath.Base(file.Path)))
+	assert.Equal(t, string(current), string(file.Content))
+}
+
+func TestRender__doesNotCompile(t *testing.T) {
+	_, err := structmorph.Render("domain.Person", "api.PersonDTO", structmorph.WithProjectRoot("typecheckfail"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "generated code does not compile")
+	assert.Contains(t, err.Error(), "mapping domain.Person.Status -> PersonDTO.Status")
+	assert.NotContains(t, err.Error(), "Person.Name")
+}
+
+func TestGenerate__doesNotCompile(t *testing.T) {
+	err := structmorph.Generate("domain.Person", "api.PersonDTO", structmorph.WithProjectRoot("typecheckfail"))
+
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join("typecheckfail", "api", "morph_person.go"))
+}
+
+func TestGenerate__refuseToOverwriteHandWrittenFile(t *testing.T) {
+	fileName := filepath.Join("handwritten", "morph_person.go")
+	before, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	err = structmorph.Generate("handwritten.Person", "handwritten.PersonDTO", structmorph.WithProjectRoot("handwritten"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to overwrite file not generated by structmorph")
+	after, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestVerify(t *testing.T) {
+	tampered, err := structmorph.Verify(".")
+
+	require.NoError(t, err)
+	assert.Empty(t, tampered)
+}
+
+func TestRenderAll(t *testing.T) {
+	// every generated file is rendered again from its header in a single packages load
+	files, err := structmorph.RenderAll(".")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		current, err := os.ReadFile(file.Path)
+		require.NoError(t, err)
+		assert.Equal(t, string(current), string(file.Content), file.Path)
+	}
+}
+
+func TestRenderAnnotated(t *testing.T) {
+	files, err := structmorph.RenderAnnotated("annotated")
+
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, file := range files {
+		current, err := os.ReadFile(file.Path)
+		require.NoError(t, err)
+		assert.Equal(t, string(current), string(file.Content), file.Path)
+	}
+}
+
+func TestGenerate__annotated(t *testing.T) {
+	// Setup
+	order := domain.Order{}
+	err := faker.FakeData(&order)
+	require.NoError(t, err)
+	address := annotated.Address{}
+	err = faker.FakeData(&address)
+	require.NoError(t, err)
+
+	// When
+	orderDTO := annotated.ConvertToOrderDTO(order)
+	addressDTO := annotated.ConvertToAddressDTO(address)
+
+	// Then
+	assert.Equal(t, order.ID, orderDTO.ID)
+	assert.Equal(t, *order.Total, orderDTO.Total)
+	assert.Equal(t, address.City, addressDTO.CITY)
+	assert.Equal(t, address, annotated.ConvertToAddress(addressDTO))
+}
+
+func TestGenerate__specDSL(t *testing.T) {
+	// Setup
+	person := specdomain.Person{FIO: "Ivanov Ivan", Age: 42, Password: "secret", Address: specdomain.Address{City: "Moscow"}}
+
+	// When
+	personDTO := specdsl.ConvertToPersonDTO(person)
+	convertedPerson := specdsl.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, specdsl.PersonDTO{FullName: "Ivanov Ivan", Age: "42", City: "MOSCOW", Version: 2, Source: "domain"}, personDTO)
+	assert.Equal(t, specdomain.Person{FIO: "Ivanov Ivan", Age: 42}, convertedPerson)
+}
+
+func TestRenderPairs(t *testing.T) {
+	// the same pairs as in the generator program, which is recorded in the header
+	program := structmorph.WithProgram("go run structmorph/test/pairs/gen")
+	files, err := structmorph.RenderPairs("pairs",
+		structmorph.Pair[pairsdomain.Person, pairsapi.PersonDTO](program, structmorph.WithFlattening()),
+		structmorph.Pair[pairsdomain.Address, pairsapi.AddressDTO](program),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		current, err := os.ReadFile(file.Path)
+		require.NoError(t, err)
+		assert.Equal(t, string(current), string(file.Content), file.Path)
+	}
+}
+
+func TestRenderPairs__notStruct(t *testing.T) {
+	_, err := structmorph.RenderPairs("pairs", structmorph.Pair[pairsdomain.Person, string]())
+
+	assert.ErrorContains(t, err, "pair type is not a named struct: string")
+}
+
+func TestGenerate__pairs(t *testing.T) {
+	// Setup
+	person := pairsdomain.Person{Name: "Ivan", Address: &pairsdomain.Address{City: "Moscow", Street: "Tverskaya"}}
+
+	// When
+	personDTO := pairsapi.ConvertToPersonDTO(person)
+	convertedPerson := pairsapi.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, pairsapi.PersonDTO{Name: "Ivan", AddressCity: "Moscow"}, personDTO)
+	assert.Equal(t, pairsdomain.Person{Name: "Ivan", Address: &pairsdomain.Address{City: "Moscow"}}, convertedPerson)
+	assert.Equal(t, pairsdomain.Person{Name: "Ivan"}, pairsapi.ConvertToPerson(pairsapi.PersonDTO{Name: "Ivan"}))
+}
+
+func TestGenerate__customTemplate(t *testing.T) {
+	// Setup
+	age := 42
+	person := customtemplate.Person{Name: "Ivan", Age: &age}
+
+	// When
+	personDTO := customtemplate.ConvertToPersonDTO(person)
+	convertedPerson := customtemplate.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, customtemplate.PersonDTO{Name: "Ivan", Age: 42}, personDTO)
+	assert.Equal(t, person, convertedPerson)
+}
+
+func TestRender__templateWithoutHeader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "morph.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte("package {{.DistFilePkgName}}\n"), 0644))
+
+	_, err := structmorph.Render("customtemplate.Person", "customtemplate.PersonDTO",
+		structmorph.WithProjectRoot("customtemplate"), structmorph.WithTemplate(file))
+
+	assert.ErrorContains(t, err, "generated code must start with the header")
+}
+
+func TestGenerate__methods(t *testing.T) {
+	// Setup
+	person := methods.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+
+	// When
+	personDTO := person.ToPersonDTO()
+	convertedPerson := personDTO.ToPerson()
+
+	// Then
+	assert.Equal(t, person, convertedPerson)
+}
+
+func TestGenerate__methodsOtherPackage(t *testing.T) {
+	// Setup
+	person := methods.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+
+	// When
+	response := methodsapi.PersonResponseFromPerson(person)
+	convertedPerson := response.ToPerson()
+
+	// Then
+	assert.Equal(t, person.Name, convertedPerson.Name)
+	assert.Equal(t, person.Age, convertedPerson.Age)
+	assert.Nil(t, convertedPerson.Address)
+}
+
+// stubMapper overrides a single conversion of the generated group, as the mocks of the services do.
+type stubMapper struct {
+	converter.MapperImpl
+}
+
+func (stubMapper) ToPersonDTO(src converter.Person) converter.PersonDTO {
+	return converter.PersonDTO{Name: "stub"}
+}
+
+func TestGenerate__converterGroup(t *testing.T) {
+	// Setup
+	person := converter.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+	order := converter.Order{}
+	err = faker.FakeData(&order)
+	require.NoError(t, err)
+
+	var mapper converter.Mapper = converter.MapperImpl{}
+
+	// When
+	personDTO := mapper.ToPersonDTO(person)
+	orderDTO := mapper.ToOrderDTO(order)
+
+	// Then
+	assert.Equal(t, converter.ConvertToPersonDTO(person), personDTO)
+	assert.Equal(t, person, mapper.ToPerson(personDTO))
+	assert.Equal(t, order.ToOrderDTO(), orderDTO)
+	assert.Equal(t, order, mapper.ToOrder(orderDTO))
+	assert.Zero(t, unsafe.Sizeof(converter.MapperImpl{}))
+
+	mapper = stubMapper{}
+	assert.Equal(t, "stub", mapper.ToPersonDTO(person).Name)
+	assert.Equal(t, order, mapper.ToOrder(mapper.ToOrderDTO(order)))
+}
+
+func TestGenerate__converter(t *testing.T) {
+	// Setup
+	address := converter.Address{}
+	err := faker.FakeData(&address)
+	require.NoError(t, err)
+
+	var addressConverter converter.AddressConverter = converter.AddressConverterImpl{}
+
+	// When
+	addressDTO := addressConverter.ToAddressDTO(address)
+
+	// Then
+	assert.Equal(t, address.City, addressDTO.City)
+	assert.Equal(t, address, addressConverter.ToAddress(addressDTO))
+}
+
+func TestGenerate__ptrVariants(t *testing.T) {
+	// Setup
+	person := ptrvariants.Person{}
+	err := faker.FakeData(&person)
+	require.NoError(t, err)
+	person.Billing = nil
+
+	// When
+	personDTO := ptrvariants.ConvertToPersonDTOPtr(&person)
+	convertedPerson := ptrvariants.ConvertToPersonPtr(personDTO)
+
+	// Then
+	require.NotNil(t, personDTO.Home)
+	assert.Equal(t, person.Home.City, personDTO.Home.City)
+	assert.Equal(t, person.Work.Street, personDTO.Work.Street)
+	assert.Nil(t, personDTO.Billing)
+	assert.Equal(t, person, *convertedPerson)
+	assert.Nil(t, ptrvariants.ConvertToPersonDTOPtr(nil))
+	assert.Nil(t, ptrvariants.ConvertToPersonPtr(nil))
+}
+
+func TestGenerate__collections(t *testing.T) {
+	// Setup
+	order := collections.Order{}
+	err := faker.FakeData(&order, options.WithRandomMapAndSliceMinSize(1))
+	require.NoError(t, err)
+
+	// When
+	orderDTO := collections.ConvertToOrderDTO(order)
+	orderDTOs := collections.ConvertToOrderDTOs([]collections.Order{order})
+	orderDTOsByID := collections.ConvertToOrderDTOMap(map[string]collections.Order{order.ID: order})
+
+	// Then
+	require.Len(t, orderDTO.Items, len(order.Items))
+	assert.Equal(t, order.Items[0].SKU, orderDTO.Items[0].SKU)
+	require.Len(t, orderDTO.Gifts, len(order.Gifts))
+	assert.Equal(t, order.Gifts[0].Quantity, orderDTO.Gifts[0].Quantity)
+	assert.Len(t, orderDTO.ItemsBySKU, len(order.ItemsBySKU))
+	assert.Equal(t, order, collections.ConvertToOrder(orderDTO))
+	assert.Equal(t, []collections.OrderDTO{orderDTO}, orderDTOs)
+	assert.Equal(t, map[string]collections.OrderDTO{order.ID: orderDTO}, orderDTOsByID)
+	assert.Equal(t, []collections.Order{order}, collections.ConvertToOrders(orderDTOs))
+}
+
+func TestGenerate__collectionsNil(t *testing.T) {
+	// When
+	orderDTO := collections.ConvertToOrderDTO(collections.Order{ID: "1", Items: []collections.Item{}})
+
+	// Then
+	assert.NotNil(t, orderDTO.Items)
+	assert.Nil(t, orderDTO.Gifts)
+	assert.Nil(t, orderDTO.ItemsBySKU)
+	assert.Nil(t, collections.ConvertToItemDTOs(nil))
+	assert.Nil(t, collections.ConvertToItemPtrs(nil))
+	assert.Nil(t, collections.ConvertToItemMap[int](nil))
+	assert.Equal(t, []*collections.ItemDTO{nil}, collections.ConvertToItemDTOPtrs([]*collections.Item{nil}))
+}
+
+func TestGenerate__patch(t *testing.T) {
+	// Setup
+	email := "old@example.com"
+	person := patch.Person{ID: "1", Name: "Old", Email: &email, Age: 30, Nickname: "nick", Tags: []string{"old"},
+		Labels: map[string]string{"key": "old"}}
+	name := "New"
+	city := "Prague"
+
+	// When
+	patch.ApplyPersonPatchDTO(&person, patch.PersonPatchDTO{ID: "2", Name: &name, Age: patch.Some(31), City: &city,
+		Tags: []string{}, Lines: []string{"Main st"}})
+
+	// Then
+	assert.Equal(t, "1", person.ID, "the field that can't be absent is not applied")
+	assert.Equal(t, "New", person.Name)
+	assert.Equal(t, &email, person.Email)
+	assert.Equal(t, 31, person.Age)
+	assert.Equal(t, "nick", person.Nickname)
+	require.NotNil(t, person.Address)
+	assert.Equal(t, "Prague", person.Address.City)
+	assert.Equal(t, []string{"Main st"}, person.Address.Lines)
+	assert.Equal(t, []string{}, person.Tags, "the non-nil slice is applied even if it is empty")
+	assert.Equal(t, map[string]string{"key": "old"}, person.Labels, "the nil map is not applied")
+}
+
+func TestGenerate__patchEmpty(t *testing.T) {
+	// Setup
+	person := patch.Person{ID: "1", Name: "Old", Age: 30, Tags: []string{"old"}}
+	want := person
+
+	// When
+	patch.ApplyPersonPatchDTO(&person, patch.PersonPatchDTO{})
+
+	// Then
+	assert.Equal(t, want, person)
+}
+
+func TestGenerate__deepCopy(t *testing.T) {
+	// Setup
+	person := deepcopy.Person{}
+	err := faker.FakeData(&person, options.WithRandomMapAndSliceMinSize(1))
+	require.NoError(t, err)
+	require.NotNil(t, person.Address)
+	require.NotEmpty(t, person.Labels)
+	require.NotEmpty(t, person.Scores)
+
+	// When
+	personDTO := deepcopy.ConvertToPersonDTO(person)
+	convertedPerson := deepcopy.ConvertToPerson(personDTO)
+
+	// Then
+	assert.Equal(t, person, convertedPerson)
+	assert.NotSame(t, &person.Tags[0], &personDTO.Tags[0])
+	assert.NotSame(t, person.Address, personDTO.Address)
+	assert.NotSame(t, &person.Address.Lines[0], &personDTO.Address.Lines[0])
+	assert.NotSame(t, &person.Previous[0].Lines[0], &personDTO.Previous[0].Lines[0])
+	assert.Same(t, &person.Shared[0], &personDTO.Shared[0], "the field tagged shallow shares the slice")
+
+	for key, label := range person.Labels {
+		personDTO.Labels[key] = "changed"
+		assert.Equal(t, label, person.Labels[key])
+	}
+	for key, scores := range person.Scores {
+		score := scores[0]
+		personDTO.Scores[key][0] = score + 1
+		assert.Equal(t, score, person.Scores[key][0])
+	}
+}
+
+func TestGenerate__deepCopyRecursive(t *testing.T) {
+	// Setup
+	person := deepcopy.Person{Name: "employee", Manager: &deepcopy.Person{
+		Name: "manager", Manager: &deepcopy.Person{
+			Name: "director", Tags: []string{"board"}, Manager: &deepcopy.Person{Name: "owner"},
+		},
+	}}
+
+	// When
+	personDTO := deepcopy.ConvertToPersonDTO(person)
+
+	// Then
+	assert.Equal(t, person.Manager, personDTO.Manager)
+	assert.NotSame(t, person.Manager, personDTO.Manager)
+	assert.NotSame(t, person.Manager.Manager, personDTO.Manager.Manager)
+	assert.NotSame(t, person.Manager.Manager.Manager, personDTO.Manager.Manager.Manager)
+
+	personDTO.Manager.Manager.Tags[0] = "changed"
+	assert.Equal(t, "board", person.Manager.Manager.Tags[0])
+}
+
+func TestGenerate__deepCopyNested(t *testing.T) {
+	// Setup
+	contactDTO := deepcopy.ContactDTO{Lines: []string{"Main st"}}
+
+	// When
+	contact := deepcopy.ConvertToContact(contactDTO)
+	empty := deepcopy.ConvertToContact(deepcopy.ContactDTO{})
+
+	// Then
+	require.NotNil(t, contact.Address)
+	assert.Equal(t, contactDTO.Lines, contact.Address.Lines)
+	assert.NotSame(t, &contactDTO.Lines[0], &contact.Address.Lines[0])
+	assert.Nil(t, empty.Address)
+}
@@ -0,0 +1,10 @@
+// Package api has a Status type with the same name as in the domain package, but a different underlying type.
+// The converters for it must not be generated, since the code would not compile.
+package api
+
+type Status int
+
+type PersonDTO struct {
+	Name   string
+	Status Status
+}
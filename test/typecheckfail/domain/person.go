@@ -0,0 +1,8 @@
+package domain
+
+type Status string
+
+type Person struct {
+	Name   string
+	Status Status
+}
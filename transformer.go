@@ -0,0 +1,207 @@
+package structmorph
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// FieldTransformer converts the values of the field pairs it matches, e.g. of the domain types like money or IDs.
+// The transformers are tried in order for every mapped field, the first matching one is used, the user transformers
+// are tried before the built-in ones.
+type FieldTransformer interface {
+	// MatchField reports whether the transformer converts the values of the field pair.
+	MatchField(src SrcFieldType, dst DstFieldType) bool
+	// TransformField returns the conversion of the field in both directions. The source field may be reached through
+	// the pointer parents, the conversion to the destination struct must check them with SrcFieldType.NilGuard.
+	TransformField(field FieldMapping) (FieldTransform, error)
+}
+
+// FieldTransform is the conversion of the field in both directions.
+type FieldTransform struct {
+	// Kind is the way the field value is converted, KindCustom if empty.
+	Kind     MappingKind
+	ToDTO    Conversion
+	ToStruct Conversion
+}
+
+// Conversion is the conversion of the field value in one direction.
+type Conversion struct {
+	// Mod is the statements placed before the return statement, e.g. the declaration of the value.
+	Mod string
+	// Value is the expression of the converted value, the field is assigned as is if empty.
+	Value string
+}
+
+func WithFieldTransformers(transformers ...FieldTransformer) GenerationConfigOption {
+	return func(cfg *GenerationConfig) {
+		cfg.Transformers = append(cfg.Transformers, transformers...)
+	}
+}
+
+// builtinTransformers are tried after the user transformers.
+var builtinTransformers = []FieldTransformer{PointerTransformer(), guardTransformer{}}
+
+// matchTransformer returns the first transformer that matches the field pair or nil.
+func matchTransformer(transformers []FieldTransformer, src SrcFieldType, dst DstFieldType) FieldTransformer {
+	for _, transformer := range transformers {
+		if transformer.MatchField(src, dst) {
+			return transformer
+		}
+	}
+	return nil
+}
+
+// PointerTransformer converts the pointer field to the value field of the same type and vice versa.
+// The pointer is dereferenced only if it is not nil and the address of the value is taken only if it is not zero.
+func PointerTransformer() FieldTransformer {
+	return pointerTransformer{}
+}
+
+type pointerTransformer struct{}
+
+func (pointerTransformer) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	return isFromPtrToValue(src, dst) || isFromValueToPtr(src, dst)
+}
+
+func (pointerTransformer) TransformField(field FieldMapping) (FieldTransform, error) {
+	guard := nilGuard(field.SrcField)
+	field.SrcField.OverriddenName = SyntheticName(field.SrcField.Name)
+	field.DstField.OverriddenName = SyntheticName(field.DstField.Name)
+
+	if isFromPtrToValue(field.SrcField, field.DstField) {
+		deref, err := renderDeref(field.SrcField.FieldType, field.typeName(field.SrcField.Type), guard)
+		if err != nil {
+			return FieldTransform{}, fmt.Errorf("error rendering deref: %w", err)
+		}
+		ref, err := renderRef(field.DstField.FieldType, field.typeName(field.DstField.Type), nil)
+		if err != nil {
+			return FieldTransform{}, fmt.Errorf("error rendering ref: %w", err)
+		}
+		return FieldTransform{
+			Kind:     KindDeref,
+			ToDTO:    Conversion{Mod: deref, Value: field.SrcField.OverriddenName},
+			ToStruct: Conversion{Mod: ref, Value: field.DstField.OverriddenName},
+		}, nil
+	}
+
+	ref, err := renderRef(field.SrcField.FieldType, field.typeName(field.SrcField.Type), guard)
+	if err != nil {
+		return FieldTransform{}, fmt.Errorf("error rendering ref: %w", err)
+	}
+	deref, err := renderDeref(field.DstField.FieldType, field.typeName(field.DstField.Type), nil)
+	if err != nil {
+		return FieldTransform{}, fmt.Errorf("error rendering deref: %w", err)
+	}
+	return FieldTransform{
+		Kind:     KindRef,
+		ToDTO:    Conversion{Mod: ref, Value: field.SrcField.OverriddenName},
+		ToStruct: Conversion{Mod: deref, Value: field.DstField.OverriddenName},
+	}, nil
+}
+
+// guardTransformer reads the nested source field reached through the pointer parents only if none of them is nil.
+type guardTransformer struct{}
+
+func (guardTransformer) MatchField(src SrcFieldType, _ DstFieldType) bool {
+	return len(nilGuard(src)) > 0
+}
+
+func (guardTransformer) TransformField(field FieldMapping) (FieldTransform, error) {
+	field.SrcField.OverriddenName = SyntheticName(field.SrcField.Name)
+	read, err := renderGuard(field.SrcField.FieldType, field.typeName(field.SrcField.Type), nilGuard(field.SrcField))
+	if err != nil {
+		return FieldTransform{}, fmt.Errorf("error rendering guard: %w", err)
+	}
+	return FieldTransform{
+		Kind:  KindGuarded,
+		ToDTO: Conversion{Mod: read, Value: field.SrcField.OverriddenName},
+	}, nil
+}
+
+// NestedTransformer converts the nested struct fields of different types, e.g. *Address to *AddressDTO,
+// with the conversion functions generated for their struct pair into the destination package, the pointer fields
+// with the pointer variants and the slice and map fields with the collection variants. The struct pair must be
+// generated without the methods, and with the pointer and collection variants for such fields.
+func NestedTransformer() FieldTransformer {
+	return nestedTransformer{}
+}
+
+type nestedTransformer struct{}
+
+func (nestedTransformer) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	srcNested, srcOk := nestedStructOf(src.Type)
+	dstNested, dstOk := nestedStructOf(dst.Type)
+	return srcOk && dstOk && srcNested.variant == dstNested.variant && !types.Identical(srcNested.elem, dstNested.elem) &&
+		(srcNested.key == nil || types.Identical(srcNested.key, dstNested.key))
+}
+
+func (nestedTransformer) TransformField(field FieldMapping) (FieldTransform, error) {
+	if strings.Contains(field.DstField.Type.Name, ".") {
+		return FieldTransform{}, fmt.Errorf("nested struct is not declared in the destination package, field: %s, type: %s",
+			field.DstField.Name, field.DstField.Type.Name)
+	}
+	srcNested, _ := nestedStructOf(field.SrcField.Type)
+	dstNested, _ := nestedStructOf(field.DstField.Type)
+
+	toDTO := Conversion{Value: fmt.Sprintf("%s(src.%s)", dstNested.funcName(), field.SrcField.Name)}
+	if guard := nilGuard(field.SrcField); len(guard) > 0 {
+		field.SrcField.OverriddenName = SyntheticName(field.SrcField.Name)
+		read, err := renderGuard(field.SrcField.FieldType, field.typeName(field.SrcField.Type), guard)
+		if err != nil {
+			return FieldTransform{}, fmt.Errorf("error rendering guard: %w", err)
+		}
+		toDTO = Conversion{Mod: read, Value: fmt.Sprintf("%s(%s)", dstNested.funcName(), field.SrcField.OverriddenName)}
+	}
+	return FieldTransform{
+		Kind:     KindNested,
+		ToDTO:    toDTO,
+		ToStruct: Conversion{Value: fmt.Sprintf("%s(src.%s)", srcNested.funcName(), field.DstField.Name)},
+	}, nil
+}
+
+// nestedStruct is the named struct type of the nested field, possibly the element of the slice or map.
+type nestedStruct struct {
+	elem types.Type
+	// key is the key type of the map, it is nil for the other fields.
+	key types.Type
+	// variant is the suffix of the conversion converting the field, e.g. Ptr or Map.
+	variant string
+}
+
+// nestedStructOf returns the nested struct of the field, the pointer, the slice of the values or pointers,
+// or the map of the values of the named struct type.
+func nestedStructOf(t FieldTypeType) (nestedStruct, bool) {
+	var nested nestedStruct
+	switch goType := t.GoType.(type) {
+	case *types.Slice:
+		nested.elem, nested.variant = goType.Elem(), "s"
+		if ptr, ok := nested.elem.(*types.Pointer); ok {
+			nested.elem, nested.variant = ptr.Elem(), "Ptrs"
+		}
+	case *types.Map:
+		nested.elem, nested.key, nested.variant = goType.Elem(), goType.Key(), "Map"
+	default:
+		nested.elem = goType
+		if t.IsPointer {
+			nested.variant = "Ptr"
+		}
+	}
+	named, ok := nested.elem.(*types.Named)
+	if !ok || t.IsPointer && nested.variant != "Ptr" {
+		return nestedStruct{}, false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nestedStruct{}, false
+	}
+	return nested, true
+}
+
+// funcName returns the name of the generated conversion to the nested struct.
+func (n nestedStruct) funcName() string {
+	name := "ConvertTo" + n.elem.(*types.Named).Obj().Name()
+	if n.variant == "s" {
+		return plural(name)
+	}
+	return name + n.variant
+}
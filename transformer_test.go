@@ -0,0 +1,99 @@
+package structmorph
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moneyTransformer converts the amount in cents to the decimal string and back.
+type moneyTransformer struct{}
+
+func (moneyTransformer) MatchField(src SrcFieldType, dst DstFieldType) bool {
+	return src.Type.Name == "Money" && dst.Type.Name == "string"
+}
+
+func (moneyTransformer) TransformField(field FieldMapping) (FieldTransform, error) {
+	name := SyntheticName(field.DstField.Name)
+	return FieldTransform{
+		ToDTO: Conversion{Value: fmt.Sprintf("src.%s.String()", field.SrcField.Name)},
+		ToStruct: Conversion{
+			Mod:   fmt.Sprintf("\n%s, _ := ParseMoney(src.%s)\n", name, field.DstField.Name),
+			Value: name,
+		},
+	}, nil
+}
+
+func TestCreateMods__transformer(t *testing.T) {
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "main", Name: "Order"},
+		Fields: map[string]SrcFieldType{
+			"Total": {FieldType: FieldType{Name: "Total", Type: FieldTypeType{Name: "Money"}}},
+			"Note":  {FieldType: FieldType{Name: "Note", Type: FieldTypeType{Name: "string", IsPointer: true}}},
+		},
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "main", Name: "OrderDTO"},
+		Fields: []DstFieldType{
+			{FieldType: FieldType{Name: "Total", Type: FieldTypeType{Name: "string"}}, SrcField: "Total"},
+			{FieldType: FieldType{Name: "Note", Type: FieldTypeType{Name: "string"}}, SrcField: "Note"},
+		},
+	}
+
+	_, err := CreateTemplateData(srcStruct, dstStruct, DefaultGenerationConfig())
+	require.ErrorContains(t, err, "field type mismatch")
+
+	data, err := CreateTemplateData(srcStruct, dstStruct, newGenerationConfig(WithFieldTransformers(moneyTransformer{})))
+	require.NoError(t, err)
+
+	assert.Equal(t, KindCustom, data.Fields[0].Kind)
+	assert.Equal(t, "src.Total.String()", data.Fields[0].ValueToDTO())
+	assert.Equal(t, "__synthetic__Total", data.Fields[0].ValueToStruct())
+	assert.Contains(t, data.Fields[0].ModToStruct, "ParseMoney(src.Total)")
+	assert.Equal(t, KindDeref, data.Fields[1].Kind, "built-in transformer is used for the other fields")
+
+	buff := &bytes.Buffer{}
+	require.NoError(t, data.GenerateCode(buff))
+	assert.Contains(t, buff.String(), "Total: src.Total.String(),")
+	assert.Contains(t, buff.String(), "Total: __synthetic__Total,")
+}
+
+func namedStruct(pkg *types.Package, name string) types.Type {
+	return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(nil, nil), nil)
+}
+
+func TestCreateMods__nestedTransformer(t *testing.T) {
+	pkg := types.NewPackage("example.com/module", "main")
+	srcStruct := SrcStructType{
+		StructName: StructName{Package: "main", Name: "Person"},
+		Fields: map[string]SrcFieldType{
+			"Home": {FieldType: FieldType{Name: "Home", Type: FieldTypeType{Name: "Address", IsPointer: true, GoType: namedStruct(pkg, "Address")}}},
+			"Work": {FieldType: FieldType{Name: "Work", Type: FieldTypeType{Name: "Address", GoType: namedStruct(pkg, "Address")}}},
+		},
+	}
+	dstStruct := DstStructType{
+		StructName: StructName{Package: "main", Name: "PersonDTO"},
+		Fields: []DstFieldType{
+			{FieldType: FieldType{Name: "Home", Type: FieldTypeType{Name: "AddressDTO", IsPointer: true, GoType: namedStruct(pkg, "AddressDTO")}}, SrcField: "Home"},
+			{FieldType: FieldType{Name: "Work", Type: FieldTypeType{Name: "AddressDTO", GoType: namedStruct(pkg, "AddressDTO")}}, SrcField: "Work"},
+		},
+	}
+
+	data, err := CreateTemplateData(srcStruct, dstStruct, newGenerationConfig(WithFieldTransformers(NestedTransformer())))
+	require.NoError(t, err)
+
+	assert.Equal(t, KindNested, data.Fields[0].Kind)
+	assert.Equal(t, "ConvertToAddressDTOPtr(src.Home)", data.Fields[0].ValueToDTO())
+	assert.Equal(t, "ConvertToAddressPtr(src.Home)", data.Fields[0].ValueToStruct())
+	assert.Equal(t, "ConvertToAddressDTO(src.Work)", data.Fields[1].ValueToDTO())
+	assert.Equal(t, "ConvertToAddress(src.Work)", data.Fields[1].ValueToStruct())
+
+	dstStruct.Fields[0].Type.Name = "api.AddressDTO"
+	_, err = CreateTemplateData(srcStruct, dstStruct, newGenerationConfig(WithFieldTransformers(NestedTransformer())))
+	assert.ErrorContains(t, err, "nested struct is not declared in the destination package")
+}
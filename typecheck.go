@@ -0,0 +1,175 @@
+package structmorph
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeCheck type-checks the generated file together with the rest of the destination package against the loaded
+// packages. Only the errors in the generated file are reported, each of them is attributed to the field mapping
+// that produced the erroneous code.
+func (p *Parser) TypeCheck(file GeneratedFile, dstStruct DstStructType, data TemplateData) error {
+	pkg := dstStruct.pkg
+	if pkg == nil || pkg.Types == nil {
+		// no package information, nothing to check against
+		return nil
+	}
+
+	generated, err := parser.ParseFile(pkg.Fset, file.Path, file.Content, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("error parsing generated code: %w", err)
+	}
+
+	// the previously generated file is replaced by the new one
+	files := []*ast.File{generated}
+	for _, f := range pkg.Syntax {
+		if pkg.Fset.Position(f.Pos()).Filename != file.Path {
+			files = append(files, f)
+		}
+	}
+
+	var errs []error
+	conf := types.Config{
+		Importer: p.importer(),
+		Error: func(err error) {
+			var typeErr types.Error
+			if !errors.As(err, &typeErr) || typeErr.Fset.Position(typeErr.Pos).Filename != file.Path {
+				return
+			}
+			// continuation errors, like the other declaration of a redeclared function, are indented
+			msg := strings.TrimPrefix(typeErr.Msg, "\t")
+			errs = append(errs, fmt.Errorf("%s: %s", describeMapping(typeErr.Fset, generated, typeErr.Pos, data), msg))
+		},
+	}
+	_, _ = conf.Check(pkg.Types.Path(), pkg.Fset, files, nil)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("generated code does not compile: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// FindFuncDecl returns the file of the destination package, other than the excluded one,
+// where the function with the given name is declared, or an empty string.
+func (s *DstStructType) FindFuncDecl(name, exclude string) string {
+	if s.pkg == nil {
+		return ""
+	}
+	for _, file := range s.pkg.Syntax {
+		fileName := s.pkg.Fset.Position(file.Pos()).Filename
+		if fileName == exclude {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+				return fileName
+			}
+		}
+	}
+	return ""
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func (p *Parser) importer() types.Importer {
+	loaded := make(map[string]*types.Package)
+	packages.Visit(p.pkgCache.pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types != nil {
+			loaded[pkg.Types.Path()] = pkg.Types
+		}
+	})
+
+	return importerFunc(func(path string) (*types.Package, error) {
+		if path == "unsafe" {
+			return types.Unsafe, nil
+		}
+		if pkg, ok := loaded[path]; ok {
+			return pkg, nil
+		}
+		return nil, fmt.Errorf("package is not loaded: %s", path)
+	})
+}
+
+// describeMapping returns the field mapping that produced the code at the position.
+// The fields are recognized by the keys of the struct literals and by the synthetic variables of the mods.
+func describeMapping(fset *token.FileSet, file *ast.File, pos token.Pos, data TemplateData) string {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+
+	var funcName string
+	for _, node := range path {
+		if fn, ok := node.(*ast.FuncDecl); ok {
+			funcName = fn.Name.Name
+		}
+	}
+
+	for _, node := range path {
+		kv, ok := node.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for _, field := range data.Fields {
+			if funcName == data.FuncNameToDTO && field.DstField.Name == key.Name ||
+				funcName == data.FuncNameToStruct && srcRootName(field.SrcField) == key.Name {
+				return describeField(data, field)
+			}
+		}
+	}
+
+	for i, node := range path {
+		// stop at the function body, otherwise every synthetic variable of the function would be found
+		if i+1 < len(path) {
+			if _, ok := path[i+1].(*ast.FuncDecl); ok {
+				break
+			}
+		}
+		if _, ok := node.(*ast.File); ok {
+			break
+		}
+
+		var found string
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && found == "" && strings.HasPrefix(ident.Name, "__synthetic__") {
+				found = ident.Name
+			}
+			return found == ""
+		})
+		if found == "" {
+			continue
+		}
+		for _, field := range data.Fields {
+			if field.SrcField.OverriddenName == found || field.DstField.OverriddenName == found ||
+				len(field.SrcField.Parents) > 0 && SyntheticName(field.SrcField.Parents[0].Name) == found {
+				return describeField(data, field)
+			}
+		}
+	}
+
+	return fmt.Sprintf("generated code at %s", fset.Position(pos))
+}
+
+func describeField(data TemplateData, field FieldMapping) string {
+	return fmt.Sprintf("mapping %s.%s -> %s.%s", data.SrcStructName, field.SrcField.Name, data.DstStructName, field.DstField.Name)
+}
+
+func srcRootName(field SrcFieldType) string {
+	if len(field.Parents) > 0 {
+		return field.Parents[0].Name
+	}
+	return field.Name
+}